@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/nilotpaul/gospur/config"
 	"github.com/spf13/cobra"
@@ -35,6 +36,103 @@ var (
 		Run:   handleUpdateCmd,
 	}
 
+	// Project add command
+	// On run -> gospur add [option]...
+	addCmd = &cobra.Command{
+		Use:     "add [option]...",
+		Short:   "Adds extras, a UI library or Tailwind to an existing project",
+		Example: "gospur add HTMX Dockerfile",
+		Args:    cobra.MinimumNArgs(1),
+		Run:     handleAddCmd,
+	}
+
+	// Project dev command
+	// On run -> gospur dev.
+	devCmd = &cobra.Command{
+		Use:   "dev",
+		Short: "Runs the project, rebuilding it and reloading the browser on changes",
+		Args:  cobra.NoArgs,
+		Run:   handleDevCmd,
+	}
+
+	// Project build command
+	// On run -> gospur build.
+	buildCmd = &cobra.Command{
+		Use:     "build",
+		Short:   "Builds the project for production, optionally cross-compiling it",
+		Example: "gospur build --targets linux/amd64,darwin/arm64",
+		Args:    cobra.NoArgs,
+		Run:     handleBuildCmd,
+	}
+
+	// Project doctor command
+	// On run -> gospur doctor.
+	doctorCmd = &cobra.Command{
+		Use:   "doctor",
+		Short: "Checks the toolchain and the health of the project it's run in",
+		Args:  cobra.NoArgs,
+		Run:   handleDoctorCmd,
+	}
+
+	// Project generate command
+	// On run -> gospur generate.
+	generateCmd = &cobra.Command{
+		Use:     "generate",
+		Aliases: []string{"g"},
+		Short:   "Generates code inside an existing project",
+		Args:    cobra.NoArgs,
+	}
+
+	// Generate page command
+	// On run -> gospur generate page [name].
+	generatePageCmd = &cobra.Command{
+		Use:   "page [name]",
+		Short: "Adds a page with its handler and route to a Templates project",
+		Args:  cobra.ExactArgs(1),
+		Run:   handleGeneratePageCmd,
+	}
+
+	// Generate crud command
+	// On run -> gospur generate crud [name] [field:type]...
+	generateCrudCmd = &cobra.Command{
+		Use:     "crud [name] [field:type]...",
+		Short:   "Scaffolds a resource with its model, store, handlers, pages and routes",
+		Example: "gospur generate crud Todo title:string notes:text priority:int done:bool",
+		Args:    cobra.MinimumNArgs(1),
+		Run:     handleGenerateCrudCmd,
+	}
+
+	// Generate middleware command
+	// On run -> gospur generate middleware [name].
+	generateMiddlewareCmd = &cobra.Command{
+		Use:     "middleware [name]",
+		Short:   "Adds a middleware and registers it globally or for a route group",
+		Example: "gospur generate middleware RequireAdmin --group /admin",
+		Args:    cobra.ExactArgs(1),
+		Run:     handleGenerateMiddlewareCmd,
+	}
+
+	// Generate component command
+	// On run -> gospur generate component [name].
+	generateComponentCmd = &cobra.Command{
+		Use:   "component [name]",
+		Short: "Adds a reusable HTML partial to web/components of a Templates project",
+		Args:  cobra.ExactArgs(1),
+		Run:   handleGenerateComponentCmd,
+	}
+
+	// Options catalog command
+	// On run -> gospur options.
+	optionsCmd = &cobra.Command{
+		Use:     "options",
+		Short:   "Lists every option of init with their constraints",
+		Example: "gospur options --json",
+		Args:    cobra.NoArgs,
+		Run:     handleOptionsCmd,
+		// The output is read by other programs.
+		Annotations: map[string]string{noLogoAnnotation: "true"},
+	}
+
 	// Project version command
 	// On run -> gospur version.
 	versionCmd = &cobra.Command{
@@ -45,19 +143,65 @@ var (
 	}
 )
 
+// noLogoAnnotation marks the commands which don't print the logo.
+const noLogoAnnotation = "nologo"
+
 func Execute() error {
-	fmt.Println(config.LogoColoured)
+	if printsLogo(os.Args[1:]) {
+		fmt.Println(config.LogoColoured)
+	}
 	return rootCmd.Execute()
 }
 
+// printsLogo reports whether the command run with the `args` prints the logo,
+// it's left out of the output read by other programs (eg. the shell completions).
+func printsLogo(args []string) bool {
+	if len(args) != 0 && (args[0] == cobra.ShellCompRequestCmd || args[0] == cobra.ShellCompNoDescRequestCmd) {
+		return false
+	}
+	cmd, _, err := rootCmd.Find(args)
+
+	return err != nil || cmd.Annotations[noLogoAnnotation] != "true"
+}
+
 func init() {
 	// Flags for init cmd.
 	registerInitCmdFlags()
+	// Flags for add cmd.
+	registerAddCmdFlags()
+	// Flags for build cmd.
+	registerBuildCmdFlags()
+	// Flags for options cmd.
+	registerOptionsCmdFlags()
 
+	// Flags for generate cmd.
+	registerGenerateCmdFlags()
+
+	generateCmd.AddCommand(
+		generatePageCmd,
+		generateCrudCmd,
+		generateMiddlewareCmd,
+		generateComponentCmd,
+	)
 	rootCmd.AddCommand(
 		initCmd,
+		addCmd,
+		devCmd,
+		buildCmd,
+		doctorCmd,
+		generateCmd,
+		optionsCmd,
 		updateCmd,
 		versionCmd,
 	)
 
+	// The completion scripts for bash, zsh, fish and powershell (gospur completion bash),
+	// added now rather than on execute so they're marked to not print the logo.
+	rootCmd.InitDefaultCompletionCmd()
+	if completionCmd, _, err := rootCmd.Find([]string{"completion"}); err == nil {
+		for _, cmd := range append(completionCmd.Commands(), completionCmd) {
+			cmd.Annotations = map[string]string{noLogoAnnotation: "true"}
+		}
+	}
+
 }
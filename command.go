@@ -2,17 +2,30 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"runtime"
+	"sort"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/nilotpaul/gospur/config"
 	"github.com/nilotpaul/gospur/util"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
-var stackConfig = &util.StackConfig{}
+var (
+	stackConfig    = &util.StackConfig{}
+	addConfig      = &util.AddConfig{}
+	generateConfig = &util.GenerateConfig{}
+	buildConfig    = &util.BuildConfig{}
+	optionsConfig  = &util.OptionsConfig{}
+)
 
 // handleInitCmd handles the `init` command for gospur CLI.
 func handleInitCmd(cmd *cobra.Command, args []string) {
@@ -70,6 +83,198 @@ func handleInitCmd(cmd *cobra.Command, args []string) {
 	util.PrintSuccessMsg(targetPath.Path, cfg)
 }
 
+// handleAddCmd handles the `add` command for gospur CLI.
+// It must be run from the root of a project.
+func handleAddCmd(cmd *cobra.Command, args []string) {
+	files, err := util.AddToProject(".", args, *addConfig)
+	if err != nil {
+		fmt.Println(config.ErrMsg(err))
+		return
+	}
+
+	var conflicts []string
+	for _, file := range files {
+		if file.Status == "conflict" {
+			conflicts = append(conflicts, file.Path)
+			fmt.Println(config.ErrMsg(file.Status + " " + file.Path))
+			continue
+		}
+		fmt.Println(config.FaintMsg(file.Status + " " + file.Path))
+	}
+
+	fmt.Println(config.SuccessMsg(fmt.Sprintf("\nAdded %s! 🎉\n", strings.Join(args, ", "))))
+	if len(conflicts) != 0 {
+		fmt.Println(config.NormalMsg("These files were edited and couldn't be patched, merge the changes from the .new file by hand:"))
+		fmt.Println(config.FaintMsg(strings.Join(conflicts, "\n") + "\n"))
+	}
+	fmt.Println(config.NormalMsg("Please Run:"))
+	// Only projects with Templates rendering have frontend dependencies.
+	if _, err := os.Stat("package.json"); err == nil {
+		fmt.Println(config.FaintMsg("\ngo mod tidy\nnpm install\n"))
+	} else {
+		fmt.Println(config.FaintMsg("\ngo mod tidy\n"))
+	}
+}
+
+// handleDevCmd handles the `dev` command for gospur CLI.
+// It must be run from the root of a project, it runs until interrupted.
+func handleDevCmd(cmd *cobra.Command, args []string) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := util.RunDevServer(ctx, "."); err != nil {
+		fmt.Println(config.ErrMsg(err))
+	}
+}
+
+// handleBuildCmd handles the `build` command for gospur CLI.
+// It must be run from the root of a project.
+func handleBuildCmd(cmd *cobra.Command, args []string) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	files, err := util.BuildProject(ctx, ".", *buildConfig)
+	for _, file := range files {
+		fmt.Println(config.FaintMsg("write " + file))
+	}
+	if err != nil {
+		fmt.Println(config.ErrMsg(err))
+		return
+	}
+
+	fmt.Println(config.SuccessMsg("\nProject Built! 🎉"))
+}
+
+// handleDoctorCmd handles the `doctor` command for gospur CLI.
+// It exits with a non-zero code if any check fails.
+func handleDoctorCmd(cmd *cobra.Command, args []string) {
+	failed := 0
+	for _, check := range util.RunDoctor(".") {
+		if check.Err == nil {
+			fmt.Println(config.SuccessMsg("✓ "+check.Name), config.FaintMsg(check.Found))
+			continue
+		}
+		failed++
+		fmt.Println(config.ErrMsg("✗ "+check.Name), check.Err)
+		fmt.Println(config.FaintMsg("  → " + check.Fix))
+	}
+
+	if failed != 0 {
+		fmt.Println(config.ErrMsg(fmt.Sprintf("\n%d check(s) failed", failed)))
+		os.Exit(1)
+	}
+	fmt.Println(config.SuccessMsg("\nEverything looks good! 🎉"))
+}
+
+// handleOptionsCmd handles the `options` command for gospur CLI.
+func handleOptionsCmd(cmd *cobra.Command, args []string) {
+	catalog := util.GetOptionsCatalog()
+
+	switch {
+	case optionsConfig.JSON:
+		out, err := json.MarshalIndent(catalog, "", "  ")
+		if err != nil {
+			fmt.Println(config.ErrMsg(err))
+			return
+		}
+		fmt.Println(string(out))
+	case optionsConfig.YAML:
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(catalog); err != nil {
+			fmt.Println(config.ErrMsg(err))
+		}
+	default:
+		for _, opt := range catalog.Options {
+			fmt.Println(config.NormalMsg(fmt.Sprintf("%s (--%s)", opt.Name, opt.Flag)) + formatRequires(opt.Requires))
+			for _, v := range opt.Values {
+				name := v.Value
+				if v.Name != v.Value {
+					name = fmt.Sprintf("%s (%s)", v.Value, v.Name)
+				}
+				fmt.Println("  " + name + formatRequires(v.Requires))
+			}
+		}
+	}
+}
+
+// formatRequires returns the constraints of an option, eg. [requires --render Templates].
+func formatRequires(requires map[string][]string) string {
+	if len(requires) == 0 {
+		return ""
+	}
+
+	var parts []string
+	for _, flag := range util.GetMapKeys(requires) {
+		parts = append(parts, fmt.Sprintf("--%s %s", flag, strings.Join(requires[flag], "|")))
+	}
+	sort.Strings(parts)
+
+	return " " + config.FaintMsg("[requires "+strings.Join(parts, ", ")+"]")
+}
+
+// handleGeneratePageCmd handles the `generate page` command for gospur CLI.
+// It must be run from the root of a project.
+func handleGeneratePageCmd(cmd *cobra.Command, args []string) {
+	files, err := util.GeneratePage(".", args[0], *generateConfig)
+	for _, file := range files {
+		fmt.Println(config.FaintMsg("write " + file))
+	}
+	if err != nil {
+		fmt.Println(config.ErrMsg(err))
+		return
+	}
+
+	fmt.Println(config.SuccessMsg("\nPage Generated! 🎉"))
+}
+
+// handleGenerateCrudCmd handles the `generate crud` command for gospur CLI.
+// It must be run from the root of a project.
+func handleGenerateCrudCmd(cmd *cobra.Command, args []string) {
+	files, err := util.GenerateCrud(".", args[0], args[1:])
+	for _, file := range files {
+		fmt.Println(config.FaintMsg("write " + file))
+	}
+	if err != nil {
+		fmt.Println(config.ErrMsg(err))
+		return
+	}
+
+	fmt.Println(config.SuccessMsg("\nResource Generated! 🎉"))
+}
+
+// handleGenerateMiddlewareCmd handles the `generate middleware` command for gospur CLI.
+// It must be run from the root of a project.
+func handleGenerateMiddlewareCmd(cmd *cobra.Command, args []string) {
+	files, err := util.GenerateMiddleware(".", args[0], *generateConfig)
+	for _, file := range files {
+		fmt.Println(config.FaintMsg("write " + file))
+	}
+	if err != nil {
+		fmt.Println(config.ErrMsg(err))
+		return
+	}
+
+	fmt.Println(config.SuccessMsg("\nMiddleware Generated! 🎉"))
+}
+
+// handleGenerateComponentCmd handles the `generate component` command for gospur CLI.
+// It must be run from the root of a project.
+func handleGenerateComponentCmd(cmd *cobra.Command, args []string) {
+	files, err := util.GenerateComponent(".", args[0])
+	for _, file := range files {
+		fmt.Println(config.FaintMsg("write " + file))
+	}
+	if err != nil {
+		fmt.Println(config.ErrMsg(err))
+		return
+	}
+
+	name := strings.TrimSuffix(filepath.Base(files[0]), ".html")
+	fmt.Println(config.SuccessMsg("\nComponent Generated! 🎉"))
+	fmt.Println(config.NormalMsg(fmt.Sprintf(`Render it with {{ template "components/%s" . }}`, name)))
+}
+
 // handleVersionCmd handles the `version` command for gospur CLI.
 func handleVersionCmd(cmd *cobra.Command, args []string) {
 	version, err := config.GetVersion()
@@ -48,6 +48,24 @@ var (
 	ExtraOpts = []string{
 		"HTMX",
 		"Dockerfile",
+		"Auth",
+		"OpenAPI",
+		"CI",
+		"Compose",
+		"Observability",
+		"Worker",
+		"WebSocket",
+		"I18n",
+		"Mail",
+		"Security",
+		"RateLimit",
+		"Storage",
+	}
+	// Only used with the CI extra.
+	CIProviderOpts = []string{
+		"GitHub",
+		"GitLab",
+		"Gitea",
 	}
 )
 
@@ -55,6 +73,9 @@ var (
 var (
 	ProjectBaseFiles = map[string]string{
 		"config/env.go":          "base/env.go.tmpl",
+		".env":                   "base/env.tmpl",
+		".env.example":           "base/env.example.tmpl",
+		"config/logger.go":       "base/logger.go.tmpl",
 		"web/styles/globals.css": "base/globals.css.tmpl",
 		".gitignore":             "base/gitignore.tmpl",
 		"Makefile":               "base/makefile.tmpl",
@@ -66,7 +87,147 @@ var (
 		"build_prod.go":          "base/build_prod.go.tmpl",
 		"Dockerfile":             "base/.dockerfile.tmpl",
 		".dockerignore":          "base/.dockerignore.tmpl",
+		"compose.yaml":           "base/compose.yaml.tmpl",
 		"main.go":                "base/main.go.tmpl",
+		"api/server.go":          "base/server.go.tmpl",
+
+		// Auth
+		"auth/password.go": "base/auth/password.go.tmpl",
+		"auth/session.go":  "base/auth/session.go.tmpl",
+		"auth/user.go":     "base/auth/user.go.tmpl",
+
+		// OpenAPI
+		"openapi/openapi.go":      "base/openapi/openapi.go.tmpl",
+		"openapi/openapi.yaml":    "base/openapi/openapi.yaml.tmpl",
+		"openapi/docs/index.html": "base/openapi/docs/index.html.tmpl",
+		"openapi/docs/init.js":    "base/openapi/docs/init.js.tmpl",
+
+		// CI
+		".github/workflows/ci.yml": "base/ci/github.yml.tmpl",
+		".gitlab-ci.yml":           "base/ci/gitlab.yml.tmpl",
+		".gitea/workflows/ci.yml":  "base/ci/gitea.yml.tmpl",
+
+		// Observability
+		"observability/request.go": "base/observability/request.go.tmpl",
+		"observability/tracing.go": "base/observability/tracing.go.tmpl",
+
+		// Worker
+		"worker/worker.go": "base/worker/worker.go.tmpl",
+		"worker/jobs.go":   "base/worker/jobs.go.tmpl",
+
+		// WebSocket
+		"hub/hub.go":          "base/hub/hub.go.tmpl",
+		"hub/chat.go":         "base/hub/chat.go.tmpl",
+		"web/scripts/ws.js":   "base/scripts/ws.js.tmpl",
+		"web/scripts/htmx.js": "base/scripts/htmx.js.tmpl",
+
+		// I18n
+		"i18n/i18n.go":    "base/i18n/i18n.go.tmpl",
+		"locales/en.json": "base/locales/en.json.tmpl",
+		"locales/de.json": "base/locales/de.json.tmpl",
+
+		// Mail
+		"mail/mail.go":                "base/mail/mail.go.tmpl",
+		"mail/smtp.go":                "base/mail/smtp.go.tmpl",
+		"mail/dir.go":                 "base/mail/dir.go.tmpl",
+		"mail/templates/contact.html": "base/mail/templates/contact.html.tmpl",
+		"mail/templates/contact.txt":  "base/mail/templates/contact.txt.tmpl",
+
+		// Security
+		"security/headers.go": "base/security/headers.go.tmpl",
+		"security/csrf.go":    "base/security/csrf.go.tmpl",
+		"security/cors.go":    "base/security/cors.go.tmpl",
+
+		// RateLimit
+		"ratelimit/ratelimit.go": "base/ratelimit/ratelimit.go.tmpl",
+		"ratelimit/memory.go":    "base/ratelimit/memory.go.tmpl",
+
+		// Storage
+		"storage/storage.go":      "base/storage/storage.go.tmpl",
+		"storage/disk.go":         "base/storage/disk.go.tmpl",
+		"storage/s3.go":           "base/storage/s3.go.tmpl",
+		"storage/storage_test.go": "base/storage/storage_test.go.tmpl",
+	}
+
+	// ProjectExtraFiles maps an extra option to the project files
+	// which should only be created when that option is selected.
+	ProjectExtraFiles = map[string][]string{
+		"Dockerfile": {"Dockerfile", ".dockerignore"},
+		"Compose":    {"compose.yaml"},
+		"Observability": {
+			"observability/request.go",
+			"observability/tracing.go",
+			"api/observability.go",
+		},
+		"Worker": {"worker/worker.go", "worker/jobs.go"},
+		"WebSocket": {
+			"hub/hub.go",
+			"hub/chat.go",
+			"api/websocket.go",
+			"web/scripts/ws.js",
+			"web/scripts/htmx.js",
+		},
+		"I18n": {
+			"i18n/i18n.go",
+			"locales/en.json",
+			"locales/de.json",
+			"api/i18n.go",
+		},
+		"Mail": {
+			"mail/mail.go",
+			"mail/smtp.go",
+			"mail/dir.go",
+			"mail/templates/contact.html",
+			"mail/templates/contact.txt",
+			"api/contact.go",
+			"web/Contact.html",
+		},
+		"Security": {
+			"security/headers.go",
+			"security/csrf.go",
+			"security/cors.go",
+			"api/security.go",
+		},
+		"RateLimit": {
+			"ratelimit/ratelimit.go",
+			"ratelimit/memory.go",
+			"api/ratelimit.go",
+		},
+		"Storage": {
+			"storage/storage.go",
+			"storage/disk.go",
+			"storage/s3.go",
+			"storage/storage_test.go",
+			"api/upload.go",
+		},
+		"Auth": {
+			"auth/password.go",
+			"auth/session.go",
+			"auth/user.go",
+			"api/auth.go",
+			"web/Login.html",
+			"web/Register.html",
+			"web/Account.html",
+		},
+		"OpenAPI": {
+			"openapi/openapi.go",
+			"openapi/openapi.yaml",
+			"openapi/docs/index.html",
+			"openapi/docs/init.js",
+			"api/openapi.go",
+		},
+		"CI": {
+			".github/workflows/ci.yml",
+			".gitlab-ci.yml",
+			".gitea/workflows/ci.yml",
+		},
+	}
+
+	// ProjectCIFiles maps a CI provider to its pipeline file.
+	ProjectCIFiles = map[string]string{
+		"GitHub": ".github/workflows/ci.yml",
+		"GitLab": ".gitlab-ci.yml",
+		"Gitea":  ".gitea/workflows/ci.yml",
 	}
 
 	// Template path is not required anymore for pages.
@@ -76,11 +237,35 @@ var (
 		"web/Error.html":        "",
 		"web/layouts/Root.html": "",
 		"web/instruction.md":    "",
+		"web/Login.html":        "",
+		"web/Register.html":     "",
+		"web/Account.html":      "",
+		"web/Contact.html":      "",
 	}
 
 	ProjectAPIFiles = map[string][]string{
-		"api/api.go":     {"api/api.go.echo.tmpl", "api/api.go.fiber.tmpl", "api/api.go.chi.tmpl"},
-		"api/route.go":   {"api/route.go.echo.tmpl", "api/route.go.fiber.tmpl", "api/route.go.chi.tmpl"},
-		"api/handler.go": {"api/handler.go.echo.tmpl", "api/handler.go.fiber.tmpl", "api/handler.go.chi.tmpl"},
+		"api/api.go":           {"api/api.go.echo.tmpl", "api/api.go.fiber.tmpl", "api/api.go.chi.tmpl"},
+		"api/route.go":         {"api/route.go.echo.tmpl", "api/route.go.fiber.tmpl", "api/route.go.chi.tmpl"},
+		"api/handler.go":       {"api/handler.go.echo.tmpl", "api/handler.go.fiber.tmpl", "api/handler.go.chi.tmpl"},
+		"api/auth.go":          {"api/auth.go.echo.tmpl", "api/auth.go.fiber.tmpl", "api/auth.go.chi.tmpl"},
+		"api/openapi.go":       {"api/openapi.go.echo.tmpl", "api/openapi.go.fiber.tmpl", "api/openapi.go.chi.tmpl"},
+		"api/observability.go": {"api/observability.go.echo.tmpl", "api/observability.go.fiber.tmpl", "api/observability.go.chi.tmpl"},
+		"api/websocket.go":     {"api/websocket.go.echo.tmpl", "api/websocket.go.fiber.tmpl", "api/websocket.go.chi.tmpl"},
+		"api/contact.go":       {"api/contact.go.echo.tmpl", "api/contact.go.fiber.tmpl", "api/contact.go.chi.tmpl"},
+		"api/i18n.go":          {"api/i18n.go.echo.tmpl", "api/i18n.go.fiber.tmpl", "api/i18n.go.chi.tmpl"},
+		"api/security.go":      {"api/security.go.echo.tmpl", "api/security.go.fiber.tmpl", "api/security.go.chi.tmpl"},
+		"api/ratelimit.go":     {"api/ratelimit.go.echo.tmpl", "api/ratelimit.go.fiber.tmpl", "api/ratelimit.go.chi.tmpl"},
+		"api/upload.go":        {"api/upload.go.echo.tmpl", "api/upload.go.fiber.tmpl", "api/upload.go.chi.tmpl"},
+		"api/api_test.go":      {"api/api_test.go.echo.tmpl", "api/api_test.go.fiber.tmpl", "api/api_test.go.chi.tmpl"},
+	}
+
+	// ProjectCrudFiles are the files of a resource made with `gospur generate crud`.
+	// `resource` in the target path is replaced with the package name of the resource.
+	ProjectCrudFiles = map[string][]string{
+		"resource/resource.go":   {"crud/model.go.tmpl"},
+		"resource/store.go":      {"crud/store.go.tmpl"},
+		"resource/store_test.go": {"crud/store_test.go.tmpl"},
+		"api/resource.go":        {"crud/handler.go.echo.tmpl", "crud/handler.go.fiber.tmpl", "crud/handler.go.chi.tmpl"},
+		"api/resource_test.go":   {"crud/handler_test.go.tmpl"},
 	}
 )
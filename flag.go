@@ -6,6 +6,7 @@ import (
 
 	"github.com/nilotpaul/gospur/config"
 	"github.com/nilotpaul/gospur/util"
+	"github.com/spf13/cobra"
 )
 
 func registerInitCmdFlags() {
@@ -29,4 +30,61 @@ func registerInitCmdFlags() {
 		&stackConfig.ExtraOpts, "extra", []string{},
 		fmt.Sprintf("One or Many: %s", strings.Join(config.ExtraOpts, ", ")),
 	)
+	initCmd.Flags().StringVar(
+		&stackConfig.CIProvider, "ci", "",
+		fmt.Sprintf("%s (with --extra CI)", strings.Join(config.CIProviderOpts, ", ")),
+	)
+
+	// Completions of the flag values.
+	initCmd.RegisterFlagCompletionFunc("framework", completeOpts(config.WebFrameworkOpts))
+	initCmd.RegisterFlagCompletionFunc("styling", completeOpts(config.CssStrategyOpts))
+	initCmd.RegisterFlagCompletionFunc("render", completeOpts(util.CompleteRenderingOpts()))
+	initCmd.RegisterFlagCompletionFunc("ci", completeOpts(config.CIProviderOpts))
+	// Only the UI libraries supporting the --styling already set are suggested.
+	initCmd.RegisterFlagCompletionFunc("ui", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		styling, _ := cmd.Flags().GetString("styling")
+		return util.CompleteUILibraries(styling), cobra.ShellCompDirectiveNoFileComp
+	})
+	initCmd.RegisterFlagCompletionFunc("extra", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return util.CompleteList(toComplete, config.ExtraOpts), cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+func registerAddCmdFlags() {
+	addCmd.Flags().StringVar(
+		&addConfig.CIProvider, "ci", "",
+		fmt.Sprintf("%s (when adding CI)", strings.Join(config.CIProviderOpts, ", ")),
+	)
+	addCmd.RegisterFlagCompletionFunc("ci", completeOpts(config.CIProviderOpts))
+}
+
+func registerBuildCmdFlags() {
+	buildCmd.Flags().StringSliceVar(
+		&buildConfig.Targets, "targets", []string{},
+		"Cross-compile for these os/arch pairs into dist (eg. linux/amd64,darwin/arm64)",
+	)
+}
+
+func registerOptionsCmdFlags() {
+	optionsCmd.Flags().BoolVar(&optionsConfig.JSON, "json", false, "Print the options as JSON")
+	optionsCmd.Flags().BoolVar(&optionsConfig.YAML, "yaml", false, "Print the options as YAML")
+	optionsCmd.MarkFlagsMutuallyExclusive("json", "yaml")
+}
+
+// completeOpts returns a completion of a flag with the `opts`, files aren't suggested.
+func completeOpts(opts []string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return opts, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func registerGenerateCmdFlags() {
+	generatePageCmd.Flags().StringVar(
+		&generateConfig.Route, "route", "",
+		"Route of the page (default /<name>, eg. /about)",
+	)
+	generateMiddlewareCmd.Flags().StringVar(
+		&generateConfig.Group, "group", "",
+		"Use the middleware only for the routes under this path (eg. /admin)",
+	)
 }
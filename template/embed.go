@@ -10,6 +10,9 @@ var base embed.FS
 //go:embed api/*
 var api embed.FS
 
+//go:embed crud/*
+var crud embed.FS
+
 //go:embed public/golang.jpg
 var img []byte
 
@@ -21,6 +24,10 @@ func GetAPIFiles() embed.FS {
 	return api
 }
 
+func GetCrudFiles() embed.FS {
+	return crud
+}
+
 func GetGolangImage() []byte {
 	return img
 }
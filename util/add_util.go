@@ -0,0 +1,392 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nilotpaul/gospur/config"
+)
+
+// hunkContext is the number of unchanged lines kept around a hunk,
+// they're used to find where the hunk goes in an edited file.
+const hunkContext = 3
+
+// ChangedFile represents a file of the project changed by `gospur add`.
+type ChangedFile struct {
+	// Path relative to the project directory.
+	Path string
+	// Status is one of create, update, patch, remove or conflict.
+	//
+	// Files which were never edited are updated (or removed), edited files are patched
+	// with the changes. A conflict is left as it is, the new content is written to `<Path>.new`.
+	Status string
+}
+
+// hunk represents a change between two versions of a file. The `old` lines at the
+// line `start` are replaced by the `new` ones, `before` and `after` are the unchanged
+// lines around them.
+type hunk struct {
+	start  int
+	before []string
+	old    []string
+	new    []string
+	after  []string
+}
+
+// AddToProject adds the `opts` (extras, a UI library or Tailwind) to the project in `projectDir`.
+//
+// The project is rendered with its detected `StackConfig` and the one with the `opts` added,
+// only the files which differ between the two are changed. Files edited since they were created
+// are patched with the difference rather than overwritten. Nothing is written if any option is invalid.
+func AddToProject(projectDir string, opts []string, add AddConfig) ([]ChangedFile, error) {
+	cfg, err := DetectStackConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	modPath, err := readModulePath(projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	newCfg, err := addStackOpts(cfg, opts, add)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateStackConfig(newCfg); err != nil {
+		return nil, err
+	}
+
+	// Both renders share the secrets, so they don't show up as changes.
+	oldCtx, newCtx := MakeProjectCtx(cfg, modPath), MakeProjectCtx(newCfg, modPath)
+	newCtx["Secrets"] = oldCtx["Secrets"]
+
+	oldFiles, err := renderProject(cfg, oldCtx)
+	if err != nil {
+		return nil, err
+	}
+	newFiles, err := renderProject(newCfg, newCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		changed []ChangedFile
+		writes  []generatedFile
+		removes []string
+	)
+	for _, path := range sortedKeys(newFiles, oldFiles) {
+		oldContent, inOld := oldFiles[path]
+		newContent, inNew := newFiles[path]
+		if inOld && inNew && bytes.Equal(oldContent, newContent) {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(projectDir, path))
+		exists := err == nil
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		switch {
+		// Files which aren't needed anymore are only removed if they're unchanged.
+		case !inNew:
+			if exists && bytes.Equal(content, oldContent) {
+				changed = append(changed, ChangedFile{path, "remove"})
+				removes = append(removes, path)
+			}
+		// The file was deleted, thus it isn't brought back.
+		case inOld && !exists:
+		case !exists:
+			changed = append(changed, ChangedFile{path, "create"})
+			writes = append(writes, generatedFile{path, newContent})
+		case bytes.Equal(content, newContent):
+		case bytes.Equal(content, oldContent):
+			changed = append(changed, ChangedFile{path, "update"})
+			writes = append(writes, generatedFile{path, newContent})
+		default:
+			if patched, ok := patchFile(oldContent, newContent, content, inOld); ok {
+				changed = append(changed, ChangedFile{path, "patch"})
+				writes = append(writes, generatedFile{path, patched})
+				continue
+			}
+			changed = append(changed, ChangedFile{path, "conflict"})
+			writes = append(writes, generatedFile{path + ".new", newContent})
+		}
+	}
+
+	if _, err := writeGeneratedFiles(projectDir, writes); err != nil {
+		return nil, err
+	}
+	for _, path := range removes {
+		if err := os.Remove(filepath.Join(projectDir, path)); err != nil {
+			return nil, err
+		}
+	}
+
+	return changed, nil
+}
+
+// addStackOpts returns a copy of the `cfg` with the `opts` added, the
+// options are matched regardless of their case (eg. htmx -> HTMX).
+func addStackOpts(cfg StackConfig, opts []string, add AddConfig) (StackConfig, error) {
+	newCfg := cfg
+	extras := append([]string{}, cfg.ExtraOpts...)
+
+	for _, opt := range opts {
+		switch {
+		case matchOpt(opt, config.ExtraOpts) != "":
+			extra := matchOpt(opt, config.ExtraOpts)
+			if contains(extras, extra) {
+				return cfg, fmt.Errorf("%s is already added to the project", extra)
+			}
+			extras = append(extras, extra)
+		case matchOpt(opt, GetMapKeys(config.UILibraryOpts)) != "":
+			lib := matchOpt(opt, GetMapKeys(config.UILibraryOpts))
+			if len(cfg.UILibrary) != 0 {
+				return cfg, fmt.Errorf("the project already uses the %s UI library", cfg.UILibrary)
+			}
+			newCfg.UILibrary = lib
+		case matchOpt(opt, config.CssStrategyOpts) != "":
+			css := matchOpt(opt, config.CssStrategyOpts)
+			if cfg.RenderingStrategy != "Templates" || (len(cfg.CssStrategy) != 0 && cfg.CssStrategy != "Vanilla") {
+				return cfg, fmt.Errorf("%s can only be added to a Templates project using Vanilla CSS", css)
+			}
+			newCfg.CssStrategy = css
+		default:
+			return cfg, fmt.Errorf("invalid option '%s', it must be an extra (%s), a UI library or Tailwind", opt, strings.Join(config.ExtraOpts, ", "))
+		}
+	}
+
+	// Keeping the extras in the order they're declared.
+	newCfg.ExtraOpts = nil
+	for _, extra := range config.ExtraOpts {
+		if contains(extras, extra) {
+			newCfg.ExtraOpts = append(newCfg.ExtraOpts, extra)
+		}
+	}
+
+	if len(newCfg.UILibrary) != 0 && !contains(config.UILibraryOpts[newCfg.UILibrary], newCfg.CssStrategy) {
+		return cfg, fmt.Errorf("%s requires Tailwind, add Tailwind4 with it", newCfg.UILibrary)
+	}
+	if contains(newCfg.ExtraOpts, "CI") && len(newCfg.CIProvider) == 0 {
+		newCfg.CIProvider = add.CIProvider
+		if len(newCfg.CIProvider) == 0 {
+			newCfg.CIProvider = config.CIProviderOpts[0]
+		}
+	} else if len(add.CIProvider) != 0 {
+		return cfg, fmt.Errorf("CI Provider can only be set when adding the CI extra")
+	}
+
+	return newCfg, nil
+}
+
+// matchOpt returns the option of `opts` equal to `v` regardless of case,
+// an empty string is returned if none match.
+func matchOpt(v string, opts []string) string {
+	for _, opt := range opts {
+		if strings.EqualFold(v, opt) {
+			return opt
+		}
+	}
+
+	return ""
+}
+
+// renderProject creates a project with the `cfg` in a temporary directory and returns
+// the content of its files by their (slash separated) path relative to the project directory.
+func renderProject(cfg StackConfig, data interface{}) (map[string][]byte, error) {
+	dir, err := os.MkdirTemp("", "gospur-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := CreateProject(dir, cfg, data); err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte)
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = content
+		return nil
+	})
+
+	return files, err
+}
+
+// sortedKeys returns the keys of every map of `files` once, sorted.
+func sortedKeys(files ...map[string][]byte) []string {
+	var keys []string
+	for _, m := range files {
+		for key := range m {
+			if !contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// patchFile applies the difference between the `oldContent` and `newContent` of
+// a file to its edited `content`, false is returned if the changes don't fit.
+// A file which wasn't part of the old render (`inOld`) can't be patched.
+func patchFile(oldContent, newContent, content []byte, inOld bool) ([]byte, bool) {
+	if !inOld {
+		return nil, false
+	}
+
+	// Comparing the lines regardless of line endings, gofmt
+	// rewrites them when a Go file is generated into.
+	crlf := bytes.Contains(content, []byte("\r\n"))
+	lines := func(b []byte) []string {
+		return strings.Split(strings.ReplaceAll(string(b), "\r\n", "\n"), "\n")
+	}
+
+	patched, ok := applyHunks(lines(content), diffLines(lines(oldContent), lines(newContent)))
+	if !ok {
+		return nil, false
+	}
+
+	result := strings.Join(patched, "\n")
+	if crlf {
+		result = strings.ReplaceAll(result, "\n", "\r\n")
+	}
+
+	return []byte(result), true
+}
+
+// diffLines returns the hunks changing the lines `a` into `b`,
+// it's based on their longest common subsequence.
+func diffLines(a, b []string) []hunk {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var (
+		hunks []hunk
+		curr  *hunk
+		i, j  int
+	)
+	for i != len(a) || j != len(b) {
+		if i != len(a) && j != len(b) && a[i] == b[j] {
+			curr = nil
+			i++
+			j++
+			continue
+		}
+		if curr == nil {
+			hunks = append(hunks, hunk{start: i})
+			curr = &hunks[len(hunks)-1]
+		}
+		if j == len(b) || (i != len(a) && lcs[i+1][j] >= lcs[i][j+1]) {
+			curr.old = append(curr.old, a[i])
+			i++
+		} else {
+			curr.new = append(curr.new, b[j])
+			j++
+		}
+	}
+
+	// The context stops at the neighbouring hunks, as their lines are changed.
+	for k := range hunks {
+		h := &hunks[k]
+		from, to := 0, len(a)
+		if k != 0 {
+			from = hunks[k-1].start + len(hunks[k-1].old)
+		}
+		if k != len(hunks)-1 {
+			to = hunks[k+1].start
+		}
+		end := h.start + len(h.old)
+		h.before = a[max(from, h.start-hunkContext):h.start]
+		h.after = a[end:min(to, end+hunkContext)]
+	}
+
+	return hunks
+}
+
+// applyHunks applies the `hunks` in order to the `lines`, each is placed where its
+// lines and context are found. If the full context isn't found, only the context
+// before or after it is matched. False is returned if any hunk can't be placed.
+func applyHunks(lines []string, hunks []hunk) ([]string, bool) {
+	var (
+		result []string
+		cursor int
+	)
+	for _, h := range hunks {
+		placed := false
+		for _, ctx := range [][2][]string{{h.before, h.after}, {h.before, nil}, {nil, h.after}} {
+			before, after := ctx[0], ctx[1]
+			// A hunk isn't placed without anything to match.
+			if len(before)+len(h.old)+len(after) == 0 {
+				continue
+			}
+
+			seq := append(append(append([]string{}, before...), h.old...), after...)
+			idx := indexLines(lines, seq, cursor)
+			if idx == -1 {
+				continue
+			}
+
+			start := idx + len(before)
+			result = append(result, lines[cursor:start]...)
+			result = append(result, h.new...)
+			cursor = start + len(h.old)
+			placed = true
+			break
+		}
+		if !placed {
+			return nil, false
+		}
+	}
+
+	return append(result, lines[cursor:]...), true
+}
+
+// indexLines returns the index of the first occurrence of `seq`
+// in `lines` starting at `from`, -1 if it isn't present.
+func indexLines(lines, seq []string, from int) int {
+	for i := from; i+len(seq) <= len(lines); i++ {
+		match := true
+		for k := range seq {
+			if lines[i+k] != seq[k] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,113 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddToProject(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	cfg := StackConfig{
+		WebFramework:      "Echo",
+		CssStrategy:       "Vanilla",
+		RenderingStrategy: "Templates",
+	}
+	dir := createTestProject(t, cfg)
+
+	files, err := AddToProject(dir, []string{"htmx", "Dockerfile"}, AddConfig{})
+	a.NoError(err)
+	a.Contains(files, ChangedFile{"package.json", "update"})
+	a.Contains(files, ChangedFile{"Dockerfile", "create"})
+
+	// An unedited project ends up like one created with the options.
+	cfg.ExtraOpts = []string{"HTMX", "Dockerfile"}
+	detected, err := DetectStackConfig(dir)
+	a.NoError(err)
+	a.Equal(cfg, detected)
+
+	want, err := renderProject(cfg, MakeProjectCtx(cfg, "example.com/app"))
+	a.NoError(err)
+	for path, content := range want {
+		got, err := os.ReadFile(filepath.Join(dir, path))
+		a.NoError(err)
+		a.Equal(string(content), string(got), path)
+	}
+
+	// Edited files are patched, a page was added to the routes.
+	_, err = GeneratePage(dir, "About", GenerateConfig{})
+	a.NoError(err)
+	files, err = AddToProject(dir, []string{"Auth"}, AddConfig{})
+	a.NoError(err)
+	a.Contains(files, ChangedFile{"api/route.go", "patch"})
+
+	route, err := os.ReadFile(filepath.Join(dir, "api", "route.go"))
+	a.NoError(err)
+	a.Contains(string(route), `router.Add("GET", "/about", handleGetAbout)`)
+	a.Contains(string(route), `router.Add("GET", "/login", h.handleGetLogin)`)
+
+	// Already added, invalid or incompatible options.
+	for _, opts := range [][]string{{"HTMX"}, {"Bootstrap"}, {"Preline"}, {"OpenAPI"}} {
+		_, err := AddToProject(dir, opts, AddConfig{})
+		a.Error(err, opts)
+	}
+	_, err = AddToProject(dir, []string{"Mail"}, AddConfig{CIProvider: "GitLab"})
+	a.Error(err)
+}
+
+func TestAddToProjectConflict(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	dir := createTestProject(t, StackConfig{
+		WebFramework:      "Chi",
+		CssStrategy:       "Tailwind4",
+		RenderingStrategy: "Templates",
+	})
+
+	// The layout is rewritten, the head scripts of HTMX can't be placed.
+	layout := filepath.Join(dir, "web", "layouts", "Root.html")
+	a.NoError(os.WriteFile(layout, []byte("<html>{{ embed .Page . }}</html>\n"), 0o644))
+
+	files, err := AddToProject(dir, []string{"HTMX", "CI"}, AddConfig{CIProvider: "Gitea"})
+	a.NoError(err)
+	a.Contains(files, ChangedFile{"web/layouts/Root.html", "conflict"})
+	a.Contains(files, ChangedFile{".gitea/workflows/ci.yml", "create"})
+
+	content, err := os.ReadFile(layout)
+	a.NoError(err)
+	a.Equal("<html>{{ embed .Page . }}</html>\n", string(content))
+	a.FileExists(layout + ".new")
+}
+
+func TestPatchFile(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	var (
+		oldContent = "package api\n\nfunc routes() {\n\tget(\"/\")\n}\n"
+		newContent = "package api\n\nfunc routes() {\n\tget(\"/\")\n\tget(\"/login\")\n}\n"
+	)
+
+	// The context after the change was edited, the one before still matches.
+	patched, ok := patchFile([]byte(oldContent), []byte(newContent), []byte("package api\n\nfunc routes() {\n\tget(\"/\")\n\tget(\"/about\")\n}\n"), true)
+	a.True(ok)
+	a.Equal("package api\n\nfunc routes() {\n\tget(\"/\")\n\tget(\"/login\")\n\tget(\"/about\")\n}\n", string(patched))
+
+	// Line endings of the edited file are kept.
+	patched, ok = patchFile([]byte(oldContent), []byte(newContent), []byte("// Routes\r\npackage api\r\n\r\nfunc routes() {\r\n\tget(\"/\")\r\n}\r\n"), true)
+	a.True(ok)
+	a.Equal("// Routes\r\npackage api\r\n\r\nfunc routes() {\r\n\tget(\"/\")\r\n\tget(\"/login\")\r\n}\r\n", string(patched))
+
+	// The changed lines are gone.
+	_, ok = patchFile([]byte(oldContent), []byte(newContent), []byte("package api\n\nfunc handlers() {}\n"), true)
+	a.False(ok)
+
+	// Files which weren't rendered before can't be patched.
+	_, ok = patchFile(nil, []byte(newContent), []byte(oldContent), false)
+	a.False(ok)
+}
@@ -0,0 +1,172 @@
+package util
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// buildTargetRe matches a cross-compilation target, eg. linux/amd64.
+var buildTargetRe = regexp.MustCompile(`^[a-z0-9]+/[a-z0-9]+$`)
+
+// BuildInfo represents the version info stamped in a production binary,
+// it's set on the `version`, `commit` and `buildTime` vars of the main package.
+type BuildInfo struct {
+	Version   string
+	Commit    string
+	BuildTime string
+}
+
+// BuildProject builds the project in `projectDir` for production and returns
+// the paths of the built files, relative to the project directory.
+//
+// The assets are bundled with esbuild (Templates rendering) and embedded with the pages
+// in the binary, which is written to `bin/build`. With `Targets`, a binary is cross-compiled
+// for each of them to `dist/<name>_<os>_<arch>` instead, along with `dist/checksums.txt`.
+func BuildProject(ctx context.Context, projectDir string, build BuildConfig) ([]string, error) {
+	cfg, err := DetectStackConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	modPath, err := readModulePath(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, target := range build.Targets {
+		if !buildTargetRe.MatchString(target) {
+			return nil, fmt.Errorf("invalid target '%s', it must be os/arch (eg. linux/amd64)", target)
+		}
+	}
+
+	switch cfg.RenderingStrategy {
+	case "Templates":
+		if err := runBuildCmd(ctx, projectDir, nil, "node", "./esbuild.config.js"); err != nil {
+			return nil, fmt.Errorf("bundling failed: %v", err)
+		}
+	case "Seperate":
+		// The client is built by its own tooling, only its output is embedded.
+		entries, err := os.ReadDir(filepath.Join(projectDir, "web", "dist"))
+		if err != nil || len(entries) == 0 {
+			return nil, fmt.Errorf("web/dist is empty, build the client into it first")
+		}
+	}
+
+	ldflags := buildLDFlags(readBuildInfo(ctx, projectDir))
+	if len(build.Targets) == 0 {
+		out := "bin/build"
+		if runtime.GOOS == "windows" {
+			out += ".exe"
+		}
+		if err := runBuildCmd(ctx, projectDir, nil, "go", "build", "-trimpath", "-ldflags", ldflags, "-o", out, "."); err != nil {
+			return nil, fmt.Errorf("build failed: %v", err)
+		}
+		return []string{out}, nil
+	}
+
+	// The binaries are named after the module, eg. example.com/app -> app_linux_amd64.
+	name := path.Base(modPath)
+	var files []string
+	for _, target := range build.Targets {
+		goos, goarch, _ := strings.Cut(target, "/")
+		out := fmt.Sprintf("dist/%s_%s_%s", name, goos, goarch)
+		if goos == "windows" {
+			out += ".exe"
+		}
+
+		env := []string{"GOOS=" + goos, "GOARCH=" + goarch, "CGO_ENABLED=0"}
+		if err := runBuildCmd(ctx, projectDir, env, "go", "build", "-trimpath", "-ldflags", ldflags, "-o", out, "."); err != nil {
+			return files, fmt.Errorf("build for %s failed: %v", target, err)
+		}
+		files = append(files, out)
+	}
+
+	if err := writeChecksums(projectDir, "dist/checksums.txt", files); err != nil {
+		return files, err
+	}
+
+	return append(files, "dist/checksums.txt"), nil
+}
+
+// runBuildCmd runs a build command in the project directory with the `env`
+// added to the current environment, its output is shown as is.
+func runBuildCmd(ctx context.Context, projectDir string, env []string, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = projectDir
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+
+	return cmd.Run()
+}
+
+// readBuildInfo returns the version info of the project from git, the version is
+// described by the latest tag. Outside of a git repository it's `dev` and the commit `none`.
+func readBuildInfo(ctx context.Context, projectDir string) BuildInfo {
+	git := func(args ...string) string {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = projectDir
+		out, err := cmd.Output()
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	info := BuildInfo{
+		Version:   git("describe", "--tags", "--always", "--dirty"),
+		Commit:    git("rev-parse", "--short", "HEAD"),
+		BuildTime: time.Now().UTC().Format(time.RFC3339),
+	}
+	if len(info.Version) == 0 {
+		info.Version = "dev"
+	}
+	if len(info.Commit) == 0 {
+		info.Commit = "none"
+	}
+
+	return info
+}
+
+// buildLDFlags returns the linker flags of a production build, the symbol table
+// and debug info are stripped and the `info` is set on the main package.
+func buildLDFlags(info BuildInfo) string {
+	return fmt.Sprintf(
+		"-s -w -X main.version=%s -X main.commit=%s -X main.buildTime=%s",
+		info.Version, info.Commit, info.BuildTime,
+	)
+}
+
+// writeChecksums writes the SHA-256 of the `files` to `out` in the format of `sha256sum`,
+// the paths are relative to the project directory and the checksums file.
+func writeChecksums(projectDir, out string, files []string) error {
+	var checksums strings.Builder
+	for _, file := range files {
+		f, err := os.Open(filepath.Join(projectDir, file))
+		if err != nil {
+			return err
+		}
+		hash := sha256.New()
+		_, err = io.Copy(hash, f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(filepath.Dir(out), file)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&checksums, "%s  %s\n", hex.EncodeToString(hash.Sum(nil)), filepath.ToSlash(rel))
+	}
+
+	return os.WriteFile(filepath.Join(projectDir, out), []byte(checksums.String()), 0o644)
+}
@@ -0,0 +1,67 @@
+package util
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildProject(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	dir := createTestProject(t, StackConfig{
+		WebFramework:      "Echo",
+		RenderingStrategy: "Seperate",
+	})
+
+	// Invalid targets are rejected before anything is built.
+	for _, target := range []string{"linux", "linux/amd64/v2", "Linux/AMD64", "/amd64"} {
+		_, err := BuildProject(context.Background(), dir, BuildConfig{Targets: []string{target}})
+		a.ErrorContains(err, "invalid target", target)
+	}
+
+	// The client has to be built into web/dist first.
+	_, err := BuildProject(context.Background(), dir, BuildConfig{})
+	a.ErrorContains(err, "web/dist")
+}
+
+func TestReadBuildInfo(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	info := readBuildInfo(context.Background(), t.TempDir())
+	a.Equal("dev", info.Version)
+	a.Equal("none", info.Commit)
+	a.NotEmpty(info.BuildTime)
+
+	a.Equal(
+		"-s -w -X main.version=v1.2.0 -X main.commit=abc1234 -X main.buildTime=2025-01-02T03:04:05Z",
+		buildLDFlags(BuildInfo{"v1.2.0", "abc1234", "2025-01-02T03:04:05Z"}),
+	)
+}
+
+func TestWriteChecksums(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	dir := t.TempDir()
+	a.NoError(os.MkdirAll(filepath.Join(dir, "dist"), 0o755))
+	a.NoError(os.WriteFile(filepath.Join(dir, "dist", "app_linux_amd64"), []byte("linux"), 0o644))
+	a.NoError(os.WriteFile(filepath.Join(dir, "dist", "app_windows_amd64.exe"), []byte("windows"), 0o644))
+
+	a.NoError(writeChecksums(dir, "dist/checksums.txt", []string{"dist/app_linux_amd64", "dist/app_windows_amd64.exe"}))
+
+	sum := func(s string) string {
+		hash := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(hash[:])
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "dist", "checksums.txt"))
+	a.NoError(err)
+	a.Equal(sum("linux")+"  app_linux_amd64\n"+sum("windows")+"  app_windows_amd64.exe\n", string(content))
+}
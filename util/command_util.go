@@ -35,6 +35,41 @@ type StackConfig struct {
 	// Flags Only
 	// Extras are extra add-ons like css lib, HTMX etc.
 	ExtraOpts []string
+	// CIProvider is the platform the CI extra generates a pipeline for.
+	CIProvider string
+}
+
+// GenerateConfig represents the options of the
+// generate commands run inside an existing project.
+type GenerateConfig struct {
+	// Route is the path a generated page is served at (eg. /about).
+	Route string
+	// Group is the path prefix of the routes a generated
+	// middleware is used for (eg. /admin), instead of every route.
+	Group string
+}
+
+// AddConfig represents the options of the `add` command,
+// which adds options to an existing project.
+type AddConfig struct {
+	// CIProvider is used when the CI extra is added (default GitHub).
+	CIProvider string
+}
+
+// BuildConfig represents the options of the `build` command,
+// which builds an existing project for production.
+type BuildConfig struct {
+	// Targets are the os/arch pairs to cross-compile for (eg. linux/amd64),
+	// the host is built for if there's none.
+	Targets []string
+}
+
+// OptionsConfig represents the options of the `options` command,
+// which prints every option of the `init` command.
+type OptionsConfig struct {
+	// JSON and YAML print the options in that format, for other programs to read.
+	JSON bool
+	YAML bool
 }
 
 // ProjectPath represents destination or location
@@ -127,6 +162,10 @@ func GetStackConfig(cfg *StackConfig) error {
 			cfg.UILibrary = uiLib
 		}
 	}
+	// CI Provider is flags only, defaulting to GitHub Actions.
+	if len(cfg.CIProvider) == 0 && contains(cfg.ExtraOpts, "CI") {
+		cfg.CIProvider = config.CIProviderOpts[0]
+	}
 
 	return nil
 }
@@ -282,22 +321,22 @@ func PrintSuccessMsg(path string, cfg StackConfig) {
 	// Post installation instructions
 	if path == "." {
 		fmt.Println(config.FaintMsg(`
-go install github.com/bokwoon95/wgo@latest
 go mod tidy
 npm install
+gospur dev
 `))
 	} else if cfg.RenderingStrategy == "Seperate" {
 		fmt.Println(config.FaintMsg(fmt.Sprintf(`
 cd %s
-go install github.com/bokwoon95/wgo@latest
 go mod tidy
+gospur dev
 `, path)))
 	} else {
 		fmt.Println(config.FaintMsg(fmt.Sprintf(`
 cd %s
-go install github.com/bokwoon95/wgo@latest
 go mod tidy
 npm install
+gospur dev
 `, path)))
 	}
 
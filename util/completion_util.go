@@ -0,0 +1,61 @@
+package util
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/nilotpaul/gospur/config"
+)
+
+// CompleteUILibraries returns the UI libraries which support the `styling`,
+// all of them if it isn't set yet.
+func CompleteUILibraries(styling string) []string {
+	var libs []string
+	for lib, deps := range config.UILibraryOpts {
+		if len(styling) == 0 || contains(deps, styling) {
+			libs = append(libs, lib)
+		}
+	}
+	sort.Strings(libs)
+
+	return libs
+}
+
+// CompleteRenderingOpts returns the values of the rendering strategies, described
+// by their names if they differ (eg. "Seperate\tSeperate Client (SPA)").
+func CompleteRenderingOpts() []string {
+	var opts []string
+	for name, value := range config.RenderingStrategy {
+		if name != value {
+			value += "\t" + name
+		}
+		opts = append(opts, value)
+	}
+	sort.Strings(opts)
+
+	return opts
+}
+
+// CompleteList completes the last value of the comma separated list `toComplete`
+// (eg. HTMX,Do -> HTMX,Dockerfile) with the `opts`, the ones already in the list are left out.
+func CompleteList(toComplete string, opts []string) []string {
+	var (
+		prefix string
+		last   = toComplete
+		chosen []string
+	)
+	if idx := strings.LastIndex(toComplete, ","); idx != -1 {
+		prefix, last = toComplete[:idx+1], toComplete[idx+1:]
+		chosen = strings.Split(toComplete[:idx], ",")
+	}
+
+	var completions []string
+	for _, opt := range opts {
+		if contains(chosen, opt) || !strings.HasPrefix(strings.ToLower(opt), strings.ToLower(last)) {
+			continue
+		}
+		completions = append(completions, prefix+opt)
+	}
+
+	return completions
+}
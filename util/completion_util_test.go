@@ -0,0 +1,37 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/nilotpaul/gospur/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompleteUILibraries(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	a.Equal([]string{"DaisyUI", "Preline"}, CompleteUILibraries(""))
+	a.Equal([]string{"DaisyUI", "Preline"}, CompleteUILibraries("Tailwind4"))
+	a.Empty(CompleteUILibraries("Vanilla"))
+}
+
+func TestCompleteRenderingOpts(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	a.Equal([]string{"Seperate\tSeperate Client (SPA)", "Templates"}, CompleteRenderingOpts())
+}
+
+func TestCompleteList(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	a.Equal(config.ExtraOpts, CompleteList("", config.ExtraOpts))
+	a.Equal([]string{"HTMX"}, CompleteList("ht", config.ExtraOpts))
+	a.Equal([]string{"HTMX,Dockerfile"}, CompleteList("HTMX,Do", config.ExtraOpts))
+	// Values already in the list aren't suggested again.
+	a.NotContains(CompleteList("HTMX,Auth,", config.ExtraOpts), "HTMX,Auth,HTMX")
+	a.Contains(CompleteList("HTMX,Auth,", config.ExtraOpts), "HTMX,Auth,CI")
+	a.Empty(CompleteList("HTMX,Zz", config.ExtraOpts))
+}
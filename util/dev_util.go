@@ -0,0 +1,396 @@
+package util
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nilotpaul/gospur/config"
+)
+
+const (
+	// devReloadPort is where `gospur dev` serves the browser reload script and events,
+	// unless `LIVE_RELOAD_PORT` is set. The server gets its url as `LIVE_RELOAD_URL`.
+	devReloadPort = "35729"
+
+	// devPollInterval is how often the project is scanned for changes, a rebuild
+	// starts once a scan finds no new changes (debounce).
+	devPollInterval = 250 * time.Millisecond
+
+	// devStopTimeout is how long the server has to shut down gracefully before it's killed.
+	devStopTimeout = 5 * time.Second
+
+	// devReadyTimeout is how long a restarted server has to start listening.
+	devReadyTimeout = 10 * time.Second
+)
+
+// devReloadScript connects to the reload events and reloads the page on every rebuild.
+const devReloadScript = `(() => {
+  const events = new EventSource(new URL("/events", document.currentScript.src));
+  events.addEventListener("reload", () => location.reload());
+})();
+`
+
+// devSkipDirs aren't watched, they hold dependencies or the output of the builds.
+var devSkipDirs = []string{".git", "bin", "build", "dist", "node_modules", "public", "tmp"}
+
+// devServer represents a project run by `gospur dev`.
+type devServer struct {
+	projectDir string
+	cfg        StackConfig
+	// exts are the file extensions which are watched.
+	exts   []string
+	reload *reloadBroker
+	// reloadURL is where the pages load the reload script from.
+	reloadURL string
+
+	// The running server, `done` is closed once it exits.
+	app  *exec.Cmd
+	done chan struct{}
+}
+
+// reloadBroker pushes reload events to the connected browsers over SSE.
+type reloadBroker struct {
+	mu      sync.Mutex
+	clients map[chan struct{}]struct{}
+}
+
+// RunDevServer runs the project in `projectDir` in development until the `ctx` is done.
+//
+// The project is built with `-tags dev` and watched for changes, on every change the
+// assets are bundled with esbuild (Templates rendering), the server is rebuilt and
+// restarted, then the browsers are reloaded over SSE.
+func RunDevServer(ctx context.Context, projectDir string) error {
+	cfg, err := DetectStackConfig(projectDir)
+	if err != nil {
+		return err
+	}
+
+	dev := &devServer{projectDir: projectDir, cfg: cfg, exts: []string{".go"}}
+	if cfg.RenderingStrategy == "Templates" {
+		dev.exts = append(dev.exts, ".html", ".css", ".js")
+		if contains(cfg.ExtraOpts, "I18n") {
+			dev.exts = append(dev.exts, ".json")
+		}
+
+		port := devEnv(projectDir, "LIVE_RELOAD_PORT", devReloadPort)
+		dev.reload = &reloadBroker{clients: make(map[chan struct{}]struct{})}
+		dev.reloadURL = "http://" + net.JoinHostPort("localhost", port)
+		srv := &http.Server{Addr: ":" + port, Handler: dev.reload}
+		go func() {
+			if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				fmt.Println(config.ErrMsg(fmt.Sprintf("browser reload is disabled: %v", err)))
+			}
+		}()
+		// The open event streams would hold up a graceful shutdown.
+		defer srv.Close()
+	}
+
+	files, err := dev.scan()
+	if err != nil {
+		return err
+	}
+	dev.rebuild(ctx, nil)
+	defer dev.stop()
+
+	ticker := time.NewTicker(devPollInterval)
+	defer ticker.Stop()
+
+	var changed []string
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		next, err := dev.scan()
+		if err != nil {
+			return err
+		}
+		if diff := changedFiles(files, next); len(diff) != 0 {
+			changed = append(changed, diff...)
+			files = next
+			continue
+		}
+		if len(changed) != 0 {
+			dev.rebuild(ctx, changed)
+			changed = nil
+		}
+	}
+}
+
+// rebuild rebuilds the project after the `changed` files, nil builds everything.
+// A failed build is reported and the running server is kept.
+func (d *devServer) rebuild(ctx context.Context, changed []string) {
+	var goChanged, pageChanged, assetChanged bool
+	for _, file := range changed {
+		switch filepath.Ext(file) {
+		case ".go":
+			goChanged = true
+		case ".html", ".json":
+			// The templates (and locales) are only loaded on startup.
+			pageChanged = true
+		default:
+			assetChanged = true
+		}
+	}
+	if changed == nil {
+		goChanged, pageChanged, assetChanged = true, true, true
+	} else {
+		fmt.Println(config.FaintMsg(fmt.Sprintf("\n%s changed, rebuilding", strings.Join(changed, ", "))))
+	}
+
+	// Tailwind picks the classes from the pages, thus they're bundled again too.
+	// The server still runs with the previous bundle if it fails.
+	if d.cfg.RenderingStrategy == "Templates" && (assetChanged || pageChanged) {
+		if err := d.run(ctx, "node", "./esbuild.config.js"); err != nil {
+			fmt.Println(config.ErrMsg(fmt.Sprintf("bundling failed: %v", err)))
+		}
+	}
+	if goChanged {
+		if err := d.run(ctx, "go", "build", "-tags", "dev", "-o", d.binPath(), "."); err != nil {
+			fmt.Println(config.ErrMsg(fmt.Sprintf("build failed: %v", err)))
+			return
+		}
+	}
+	if goChanged || pageChanged || d.app == nil {
+		d.stop()
+		if err := d.start(); err != nil {
+			fmt.Println(config.ErrMsg(fmt.Sprintf("failed to start the server: %v", err)))
+			return
+		}
+		d.waitReady()
+	}
+
+	if d.reload != nil && changed != nil {
+		d.reload.broadcast()
+	}
+}
+
+// run runs a build command in the project directory, its output is shown as is.
+func (d *devServer) run(ctx context.Context, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = d.projectDir
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+
+	return cmd.Run()
+}
+
+// start starts the built server in development.
+func (d *devServer) start() error {
+	cmd := exec.Command(d.binPath())
+	cmd.Dir = d.projectDir
+	cmd.Env = append(os.Environ(), "ENVIRONMENT=DEVELOPMENT")
+	if len(d.reloadURL) != 0 {
+		cmd.Env = append(cmd.Env, "LIVE_RELOAD_URL="+d.reloadURL)
+	}
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(done)
+	}()
+	d.app, d.done = cmd, done
+
+	return nil
+}
+
+// stop shuts the running server down gracefully, it's killed if it takes too long.
+func (d *devServer) stop() {
+	if d.app == nil {
+		return
+	}
+	defer func() { d.app = nil }()
+
+	// Interrupts can't be sent on windows.
+	if runtime.GOOS == "windows" || d.app.Process.Signal(os.Interrupt) != nil {
+		d.app.Process.Kill()
+	}
+	select {
+	case <-d.done:
+	case <-time.After(devStopTimeout):
+		d.app.Process.Kill()
+		<-d.done
+	}
+}
+
+// waitReady waits until the server listens on its port, so the
+// reloaded pages don't hit a server which isn't up yet.
+func (d *devServer) waitReady() {
+	addr := net.JoinHostPort("localhost", devEnv(d.projectDir, "PORT", "3000"))
+	deadline := time.Now().Add(devReadyTimeout)
+	for time.Now().Before(deadline) {
+		select {
+		case <-d.done:
+			return
+		default:
+		}
+		if conn, err := net.DialTimeout("tcp", addr, devPollInterval); err == nil {
+			conn.Close()
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// binPath returns the path of the built server, relative to the project directory.
+func (d *devServer) binPath() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join("bin", "build.exe")
+	}
+	return "./" + filepath.ToSlash(filepath.Join("bin", "build"))
+}
+
+// scan returns the modification time of every watched file in the project.
+func (d *devServer) scan() (map[string]time.Time, error) {
+	files := make(map[string]time.Time)
+	err := filepath.WalkDir(d.projectDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// The file was removed while walking.
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() {
+			if path != d.projectDir && contains(devSkipDirs, entry.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !contains(d.exts, filepath.Ext(path)) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(d.projectDir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = info.ModTime()
+		return nil
+	})
+
+	return files, err
+}
+
+// changedFiles returns the files which were added, modified or removed between two scans.
+func changedFiles(prev, next map[string]time.Time) []string {
+	var changed []string
+	for path, modTime := range next {
+		if prevModTime, ok := prev[path]; !ok || !prevModTime.Equal(modTime) {
+			changed = append(changed, path)
+		}
+	}
+	for path := range prev {
+		if _, ok := next[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+
+	return changed
+}
+
+// devEnv returns the variable `name` of the project, it's read from the
+// environment or the `.env` file like the server does, `fallback` if it's not set.
+func devEnv(projectDir, name, fallback string) string {
+	if value := os.Getenv(name); len(value) != 0 {
+		return value
+	}
+
+	file, err := os.Open(filepath.Join(projectDir, ".env"))
+	if err != nil {
+		return fallback
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), name+"="); ok && len(value) != 0 {
+			return strings.Trim(value, `"'`)
+		}
+	}
+
+	return fallback
+}
+
+// ServeHTTP serves the reload script at `/reload.js` and the reload events at `/events`.
+func (b *reloadBroker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/reload.js":
+		w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+		w.Write([]byte(devReloadScript))
+	case "/events":
+		b.serveEvents(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveEvents streams a reload event for every rebuild until the browser disconnects.
+func (b *reloadBroker) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	ch := make(chan struct{}, 1)
+	b.mu.Lock()
+	b.clients[ch] = struct{}{}
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		delete(b.clients, ch)
+		b.mu.Unlock()
+	}()
+
+	// The pages are served by the app on another port.
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write([]byte(": connected\n\n"))
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ch:
+			w.Write([]byte("event: reload\ndata: {}\n\n"))
+			flusher.Flush()
+		}
+	}
+}
+
+// broadcast sends a reload event to every connected browser.
+func (b *reloadBroker) broadcast() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.clients {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package util
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDevServerScan(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	dir := createTestProject(t, StackConfig{
+		WebFramework:      "Chi",
+		CssStrategy:       "Vanilla",
+		RenderingStrategy: "Templates",
+	})
+	// Dependencies and build outputs aren't watched.
+	a.NoError(os.MkdirAll(filepath.Join(dir, "node_modules", "esbuild"), 0o755))
+	a.NoError(os.WriteFile(filepath.Join(dir, "node_modules", "esbuild", "main.js"), nil, 0o644))
+	a.NoError(os.MkdirAll(filepath.Join(dir, "public", "bundle"), 0o755))
+	a.NoError(os.WriteFile(filepath.Join(dir, "public", "bundle", "globals.css"), nil, 0o644))
+
+	dev := &devServer{projectDir: dir, exts: []string{".go", ".html", ".css", ".js"}}
+	files, err := dev.scan()
+	a.NoError(err)
+	a.Contains(files, "api/route.go")
+	a.Contains(files, "web/Home.html")
+	a.Contains(files, "web/styles/globals.css")
+	a.Contains(files, "esbuild.config.js")
+	a.NotContains(files, "package.json")
+	a.NotContains(files, "node_modules/esbuild/main.js")
+	a.NotContains(files, "public/bundle/globals.css")
+}
+
+func TestChangedFiles(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	now := time.Now()
+	prev := map[string]time.Time{
+		"main.go":       now,
+		"api/route.go":  now,
+		"web/Home.html": now,
+	}
+	next := map[string]time.Time{
+		"main.go":        now,
+		"api/route.go":   now.Add(time.Second),
+		"web/About.html": now,
+	}
+
+	changed := changedFiles(prev, next)
+	sort.Strings(changed)
+	a.Equal([]string{"api/route.go", "web/About.html", "web/Home.html"}, changed)
+	a.Empty(changedFiles(next, next))
+}
+
+func TestDevEnv(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	dir := t.TempDir()
+	a.Equal("3000", devEnv(dir, "PORT", "3000"))
+
+	a.NoError(os.WriteFile(filepath.Join(dir, ".env"), []byte("ENVIRONMENT=development\nPORT=\"8080\"\nLIVE_RELOAD_PORT=\n"), 0o644))
+	a.Equal("8080", devEnv(dir, "PORT", "3000"))
+	a.Equal("35729", devEnv(dir, "LIVE_RELOAD_PORT", devReloadPort))
+}
+
+func TestReloadBroker(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	broker := &reloadBroker{clients: make(map[chan struct{}]struct{})}
+	srv := httptest.NewServer(broker)
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL + "/reload.js")
+	a.NoError(err)
+	res.Body.Close()
+	a.Equal(http.StatusOK, res.StatusCode)
+
+	res, err = http.Get(srv.URL + "/events")
+	a.NoError(err)
+	defer res.Body.Close()
+	a.Equal("text/event-stream", res.Header.Get("Content-Type"))
+	a.Equal("*", res.Header.Get("Access-Control-Allow-Origin"))
+
+	// Reading past the comment sent on connect, the client is registered by then.
+	reader := bufio.NewReader(res.Body)
+	line, err := reader.ReadString('\n')
+	a.NoError(err)
+	a.Equal(": connected\n", line)
+
+	broker.broadcast()
+	var event strings.Builder
+	for !strings.HasSuffix(event.String(), "\n\n") || event.Len() <= 2 {
+		line, err := reader.ReadString('\n')
+		if !a.NoError(err) {
+			return
+		}
+		event.WriteString(line)
+	}
+	a.Contains(event.String(), "event: reload\n")
+}
@@ -0,0 +1,351 @@
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// minGoVersion is required outside of a project or if its go.mod has
+	// no go directive, it's the version of the generated Dockerfile.
+	minGoVersion = "1.23"
+
+	// minNodeVersion is required to bundle the assets, it's the version of the
+	// generated Dockerfile and the oldest one supported by Tailwind 4.
+	minNodeVersion = "20"
+)
+
+// DoctorCheck represents a check of the environment or project run by `gospur doctor`.
+type DoctorCheck struct {
+	// Name of what's checked, eg. Go or package.json dependencies.
+	Name string
+	// Found is what was found, eg. go1.23.4.
+	Found string
+	// Err is why the check failed, nil if it passed.
+	Err error
+	// Fix is how to fix a failed check.
+	Fix string
+}
+
+// RunDoctor checks the toolchain needed by the project in `projectDir`, and
+// its health if it's a project (its go.mod or api/api.go exists).
+//
+// Outside of a project, the toolchain of the default stack (Templates rendering) is checked.
+func RunDoctor(projectDir string) []DoctorCheck {
+	inProject := fileExists(filepath.Join(projectDir, "go.mod")) ||
+		fileExists(filepath.Join(projectDir, "api", "api.go"))
+	if !inProject {
+		return []DoctorCheck{checkGo(projectDir), checkNode(), checkNpm()}
+	}
+
+	checks := []DoctorCheck{checkGo(projectDir), checkGoMod(projectDir)}
+	cfg, err := DetectStackConfig(projectDir)
+	if err != nil {
+		// The stack can't be known without the go.mod, which is reported above.
+		if fileExists(filepath.Join(projectDir, "go.mod")) {
+			checks = append(checks, DoctorCheck{
+				Name: "Stack",
+				Err:  err,
+				Fix:  "Run `gospur doctor` from the root of a project created with `gospur init`",
+			})
+		}
+		return checks
+	}
+
+	if cfg.RenderingStrategy == "Templates" {
+		checks = append(checks,
+			checkNode(),
+			checkNpm(),
+			checkPackageDeps(projectDir),
+			checkBundle(projectDir),
+			checkTemplateGlobs(projectDir, cfg),
+		)
+	} else {
+		checks = append(checks, checkClientBuild(projectDir))
+	}
+	if contains(cfg.ExtraOpts, "Dockerfile") || contains(cfg.ExtraOpts, "Compose") {
+		checks = append(checks, checkDocker())
+	}
+	// Projects created before `gospur dev` run wgo in their Makefile.
+	if makefile, err := os.ReadFile(filepath.Join(projectDir, "Makefile")); err == nil && strings.Contains(string(makefile), "wgo") {
+		checks = append(checks, checkWgo())
+	}
+
+	return checks
+}
+
+// checkGo checks that Go is installed with the version required by the project.
+func checkGo(projectDir string) DoctorCheck {
+	check := DoctorCheck{Name: "Go"}
+	out, err := exec.Command("go", "env", "GOVERSION").Output()
+	if err != nil {
+		check.Err = fmt.Errorf("go not found")
+		check.Fix = fmt.Sprintf("Install Go %s or newer from https://go.dev/dl", minGoVersion)
+		return check
+	}
+	check.Found = strings.TrimSpace(string(out))
+
+	required := readGoDirective(projectDir)
+	if len(required) == 0 {
+		required = minGoVersion
+	}
+	if compareVersions(strings.TrimPrefix(check.Found, "go"), required) == -1 {
+		check.Err = fmt.Errorf("go %s or newer is required", required)
+		check.Fix = fmt.Sprintf("Upgrade Go to %s or newer from https://go.dev/dl", required)
+	}
+
+	return check
+}
+
+// checkGoMod checks that the project has a go.mod.
+func checkGoMod(projectDir string) DoctorCheck {
+	check := DoctorCheck{Name: "go.mod", Found: "go.mod"}
+	if !fileExists(filepath.Join(projectDir, "go.mod")) {
+		check.Found = ""
+		check.Err = fmt.Errorf("go.mod not found")
+		check.Fix = "Run `go mod init <module> && go mod tidy` in the project"
+	}
+
+	return check
+}
+
+// checkNode checks that Node is installed with the version needed to bundle the assets.
+func checkNode() DoctorCheck {
+	check := DoctorCheck{Name: "Node"}
+	out, err := exec.Command("node", "--version").Output()
+	if err != nil {
+		check.Err = fmt.Errorf("node not found")
+		check.Fix = fmt.Sprintf("Install Node %s or newer from https://nodejs.org", minNodeVersion)
+		return check
+	}
+	check.Found = strings.TrimSpace(string(out))
+
+	if compareVersions(strings.TrimPrefix(check.Found, "v"), minNodeVersion) == -1 {
+		check.Err = fmt.Errorf("node %s or newer is required", minNodeVersion)
+		check.Fix = fmt.Sprintf("Upgrade Node to %s or newer from https://nodejs.org", minNodeVersion)
+	}
+
+	return check
+}
+
+// checkNpm checks that npm is installed.
+func checkNpm() DoctorCheck {
+	check := DoctorCheck{Name: "npm"}
+	out, err := exec.Command("npm", "--version").Output()
+	if err != nil {
+		check.Err = fmt.Errorf("npm not found")
+		check.Fix = "Install npm, it comes with Node from https://nodejs.org"
+		return check
+	}
+	check.Found = strings.TrimSpace(string(out))
+
+	return check
+}
+
+// checkDocker checks that Docker is installed, it builds the image of the Dockerfile and Compose extras.
+func checkDocker() DoctorCheck {
+	check := DoctorCheck{Name: "Docker"}
+	out, err := exec.Command("docker", "--version").Output()
+	if err != nil {
+		check.Err = fmt.Errorf("docker not found")
+		check.Fix = "Install Docker from https://docs.docker.com/get-docker"
+		return check
+	}
+	check.Found = strings.TrimSpace(string(out))
+
+	return check
+}
+
+// checkWgo checks that wgo is installed, it's used by `make dev` in older projects.
+func checkWgo() DoctorCheck {
+	check := DoctorCheck{Name: "wgo"}
+	path, err := exec.LookPath("wgo")
+	if err != nil {
+		check.Err = fmt.Errorf("wgo not found, the Makefile runs it")
+		check.Fix = "Run `go install github.com/bokwoon95/wgo@latest`, or replace it with `gospur dev` in the Makefile"
+		return check
+	}
+	check.Found = path
+
+	return check
+}
+
+// checkPackageDeps checks that every dependency of the package.json is installed in node_modules.
+func checkPackageDeps(projectDir string) DoctorCheck {
+	check := DoctorCheck{Name: "package.json dependencies"}
+	deps, err := readPackageDeps(projectDir)
+	if err != nil {
+		check.Err = err
+		check.Fix = "Fix the syntax of package.json"
+		return check
+	}
+
+	var missing []string
+	for _, name := range GetMapKeys(deps) {
+		if !fileExists(filepath.Join(projectDir, "node_modules", filepath.FromSlash(name), "package.json")) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) != 0 {
+		sort.Strings(missing)
+		check.Err = fmt.Errorf("%d not installed (%s)", len(missing), strings.Join(missing, ", "))
+		check.Fix = "Run `npm install`"
+		return check
+	}
+	check.Found = fmt.Sprintf("%d installed", len(deps))
+
+	return check
+}
+
+// checkBundle checks that the assets were bundled to public/bundle, they're embedded in a production build.
+func checkBundle(projectDir string) DoctorCheck {
+	check := DoctorCheck{Name: "Asset bundle", Found: "public/bundle"}
+	entries, err := os.ReadDir(filepath.Join(projectDir, "public", "bundle"))
+	if err != nil || len(entries) == 0 {
+		check.Found = ""
+		check.Err = fmt.Errorf("public/bundle is missing, the assets aren't bundled")
+		check.Fix = "Run `node ./esbuild.config.js` (`gospur build` and `gospur dev` run it too)"
+	}
+
+	return check
+}
+
+// checkClientBuild checks that a Seperate client was built to web/dist, it's embedded in a production build.
+func checkClientBuild(projectDir string) DoctorCheck {
+	check := DoctorCheck{Name: "Client build", Found: "web/dist"}
+	entries, err := os.ReadDir(filepath.Join(projectDir, "web", "dist"))
+	if err != nil || len(entries) == 0 {
+		check.Found = ""
+		check.Err = fmt.Errorf("web/dist is missing, the client isn't built")
+		check.Fix = "Build the client with its output set to web/dist"
+	}
+
+	return check
+}
+
+// checkTemplateGlobs checks that every glob of the templates loaded in api/api.go matches
+// a file, the server fails to start otherwise. Fiber loads every page of web instead.
+func checkTemplateGlobs(projectDir string, cfg StackConfig) DoctorCheck {
+	check := DoctorCheck{Name: "Template globs"}
+
+	var globs []string
+	if cfg.WebFramework == "Fiber" {
+		globs = []string{"web/*.html"}
+	} else {
+		var err error
+		globs, err = readTemplateGlobs(filepath.Join(projectDir, "api", "api.go"))
+		if err != nil {
+			check.Err = err
+			check.Fix = "Load the pages with `LoadTemplates(\"web/*.html\")` in api/api.go"
+			return check
+		}
+	}
+
+	for _, glob := range globs {
+		matches, err := filepath.Glob(filepath.Join(projectDir, filepath.FromSlash(glob)))
+		if err != nil || len(matches) == 0 {
+			check.Err = fmt.Errorf("'%s' matches no files", glob)
+			check.Fix = fmt.Sprintf("Add a page matching '%s' or remove the glob from LoadTemplates in api/api.go", glob)
+			return check
+		}
+	}
+	check.Found = strings.Join(globs, ", ")
+
+	return check
+}
+
+// readTemplateGlobs returns the globs passed as string literals to the `LoadTemplates` call of the file at `path`.
+func readTemplateGlobs(path string) ([]string, error) {
+	_, file, err := parseGoFile(token.NewFileSet(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		globs []string
+		found bool
+	)
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "LoadTemplates" {
+			found = true
+			for _, arg := range call.Args {
+				if lit, ok := arg.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					if glob, err := strconv.Unquote(lit.Value); err == nil {
+						globs = append(globs, glob)
+					}
+				}
+			}
+		}
+		return true
+	})
+	if !found {
+		return nil, fmt.Errorf("failed to find the 'LoadTemplates' call in '%s'", path)
+	}
+
+	return globs, nil
+}
+
+// readGoDirective returns the Go version of the go directive in the go.mod of `projectDir`, if any.
+func readGoDirective(projectDir string) string {
+	file, err := os.Open(filepath.Join(projectDir, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if version, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "go "); ok {
+			return strings.TrimSpace(version)
+		}
+	}
+
+	return ""
+}
+
+// compareVersions compares two dot separated versions (eg. 1.23.4), it returns -1 if
+// `a` is older than `b`, 1 if it's newer and 0 if they're equal. Missing parts are 0
+// and anything after the leading digits of a part is ignored (eg. 1.24rc1 -> 1.24).
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		var x, y int
+		if i < len(as) {
+			x = leadingInt(as[i])
+		}
+		if i < len(bs) {
+			y = leadingInt(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// leadingInt returns the number made of the leading digits of `s`, 0 if there's none.
+func leadingInt(s string) int {
+	end := 0
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	n, _ := strconv.Atoi(s[:end])
+
+	return n
+}
@@ -0,0 +1,93 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunDoctor(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	dir := createTestProject(t, StackConfig{
+		WebFramework:      "Chi",
+		CssStrategy:       "Vanilla",
+		RenderingStrategy: "Templates",
+		ExtraOpts:         []string{"Dockerfile"},
+	})
+	findCheck := func(checks []DoctorCheck, name string) DoctorCheck {
+		for _, check := range checks {
+			if check.Name == name {
+				return check
+			}
+		}
+		t.Fatalf("check '%s' wasn't run", name)
+		return DoctorCheck{}
+	}
+
+	checks := RunDoctor(dir)
+	for _, name := range []string{"Go", "go.mod", "Node", "npm", "Docker", "Template globs"} {
+		findCheck(checks, name)
+	}
+	a.NoError(findCheck(checks, "go.mod").Err)
+	a.NoError(findCheck(checks, "Template globs").Err)
+	a.ErrorContains(findCheck(checks, "package.json dependencies").Err, "esbuild")
+	a.Error(findCheck(checks, "Asset bundle").Err)
+
+	// Installed and bundled.
+	a.NoError(os.MkdirAll(filepath.Join(dir, "node_modules", "esbuild"), 0o755))
+	a.NoError(os.WriteFile(filepath.Join(dir, "node_modules", "esbuild", "package.json"), []byte("{}"), 0o644))
+	a.NoError(os.MkdirAll(filepath.Join(dir, "public", "bundle"), 0o755))
+	a.NoError(os.WriteFile(filepath.Join(dir, "public", "bundle", "globals.css"), nil, 0o644))
+	a.NoError(os.RemoveAll(filepath.Join(dir, "web", "layouts")))
+
+	checks = RunDoctor(dir)
+	a.NoError(findCheck(checks, "package.json dependencies").Err)
+	a.NoError(findCheck(checks, "Asset bundle").Err)
+	a.ErrorContains(findCheck(checks, "Template globs").Err, "web/layouts/*.html")
+
+	// A project without its go.mod.
+	a.NoError(os.Remove(filepath.Join(dir, "go.mod")))
+	checks = RunDoctor(dir)
+	a.Error(findCheck(checks, "go.mod").Err)
+	a.NotEmpty(findCheck(checks, "go.mod").Fix)
+
+	// Outside of a project only the toolchain is checked.
+	checks = RunDoctor(t.TempDir())
+	a.Len(checks, 3)
+}
+
+func TestRunDoctorSeperate(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	dir := createTestProject(t, StackConfig{
+		WebFramework:      "Fiber",
+		RenderingStrategy: "Seperate",
+	})
+	a.NoError(os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n\ngo 99.0\n"), 0o644))
+
+	checks := RunDoctor(dir)
+	a.Equal("Go", checks[0].Name)
+	a.ErrorContains(checks[0].Err, "go 99.0 or newer is required")
+	for _, check := range checks {
+		a.NotEqual("Node", check.Name)
+	}
+	a.Equal("Client build", checks[len(checks)-1].Name)
+	a.Error(checks[len(checks)-1].Err)
+}
+
+func TestCompareVersions(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	a.Equal(0, compareVersions("1.23", "1.23.0"))
+	a.Equal(1, compareVersions("1.23.4", "1.23"))
+	a.Equal(-1, compareVersions("1.9", "1.23"))
+	a.Equal(1, compareVersions("1.24rc1", "1.23.5"))
+	a.Equal(-1, compareVersions("18.20.4", "20"))
+	a.Equal(1, compareVersions("22.1.0", "20"))
+}
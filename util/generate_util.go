@@ -0,0 +1,1288 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/nilotpaul/gospur/config"
+	tmpls "github.com/nilotpaul/gospur/template"
+)
+
+// pageNameRe matches the valid names of a page, the name is
+// also used for the page handler (eg. About -> handleGetAbout).
+var pageNameRe = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+
+// majorVersionRe matches the major version suffix of an import path (eg. v4).
+var majorVersionRe = regexp.MustCompile(`^v[0-9]+$`)
+
+// frameworkImports maps a web framework to the import path it's detected by.
+var frameworkImports = map[string]string{
+	"Echo":  "github.com/labstack/echo/v4",
+	"Fiber": "github.com/gofiber/fiber/v2",
+	"Chi":   "github.com/go-chi/chi/v5",
+}
+
+const (
+	echoPageHandler = `
+func %[1]s(c echo.Context) error {
+	return c.Render(http.StatusOK, "%[2]s.html", map[string]any{
+		"Title": "%[2]s",
+	})
+}
+`
+	fiberPageHandler = `
+func %[1]s(c *fiber.Ctx) error {
+	return c.Render("%[2]s", fiber.Map{
+		"Title": "%[2]s",
+	})
+}
+`
+	chiPageHandler = `
+func %[1]s(w http.ResponseWriter, r *http.Request) {
+	templates.Render(w, r, http.StatusOK, "%[2]s.html", map[string]any{
+		"Title": "%[2]s",
+	}, "Root.html")
+}
+`
+
+	echoMiddleware = `package api
+
+import (
+	"github.com/labstack/echo/v4"
+)
+
+// %[1]s runs before the handlers of the routes it's used for.
+func %[1]s(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// Runs before the next handler, return an error to stop the request.
+
+		return next(c)
+	}
+}
+`
+	fiberMiddleware = `package api
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// %[1]s runs before the handlers of the routes it's used for.
+func %[1]s(c *fiber.Ctx) error {
+	// Runs before the next handler, return an error to stop the request.
+
+	return c.Next()
+}
+`
+	chiMiddleware = `package api
+
+import (
+	"net/http"
+)
+
+// %[1]s runs before the handlers of the routes it's used for.
+func %[1]s(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Runs before the next handler, write a response
+		// and return to stop the request.
+
+		next.ServeHTTP(w, r)
+	})
+}
+`
+)
+
+// componentsGlob is the pattern the components are loaded by, fiber
+// loads every template in the web directory, thus it isn't needed there.
+const componentsGlob = "web/components/*.html"
+
+var (
+	// crudFieldRe matches the valid keys of resource fields (eg. due_date).
+	crudFieldRe = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+	// crudFieldTypes maps the types of resource fields to their Go type.
+	// text is a string edited with a textarea.
+	crudFieldTypes = map[string]string{
+		"string": "string",
+		"text":   "string",
+		"int":    "int",
+		"float":  "float64",
+		"bool":   "bool",
+	}
+
+	// reservedCrudFields are already used by the generated model.
+	reservedCrudFields = []string{"id", "created_at", "updated_at", "fields", "validate"}
+
+	// reservedCrudPackages would shadow the imports of the generated handlers.
+	reservedCrudPackages = []string{
+		"api", "main", "chi", "echo", "fiber", "errors", "html", "http",
+		"json", "slog", "strconv", "templates",
+	}
+)
+
+// codeEdit represents `text` which needs to be inserted
+// at the byte `offset` of a Go source file.
+type codeEdit struct {
+	offset int
+	text   string
+}
+
+// generatedFile represents the `content` which will be written
+// to `path` relative to the project directory.
+type generatedFile struct {
+	path    string
+	content []byte
+}
+
+// crudResource represents a resource scaffolded with `gospur generate crud`.
+// It's passed to the crud templates, thus the fields are exported.
+type crudResource struct {
+	// Name of the model, eg. BlogPost.
+	Name string
+	// Plural of the name, used for the list page, eg. BlogPosts.
+	Plural string
+	// Package holding the model and its store, eg. blogpost.
+	Package string
+
+	// Human readable names, eg. Blog Post, Blog Posts.
+	Human       string
+	HumanPlural string
+	// Names used inside of a sentence, eg. blog post, blog posts.
+	Phrase       string
+	PluralPhrase string
+
+	// Handler is the type serving the resource, eg. blogPostHandler.
+	Handler string
+	// Var holds the handler in `RegisterRoutes`, eg. blogPosts.
+	Var string
+	// Route is the base path, eg. /blog-posts or /api/blog-posts with a seperate client.
+	Route string
+
+	Fields    []crudField
+	HasString bool
+	HasNumber bool
+}
+
+// crudField represents a field of a resource, eg. due_date:string.
+type crudField struct {
+	// Name of the struct field, eg. DueDate.
+	Name string
+	// Key of the form input and JSON, eg. due_date.
+	Key string
+	// Label shown in the pages, eg. Due date.
+	Label string
+	// Phrase used in the error messages, eg. due date.
+	Phrase string
+
+	// Type is one of `crudFieldTypes`, GoType is the type it maps to.
+	Type   string
+	GoType string
+
+	IsString bool
+	IsInt    bool
+	IsFloat  bool
+	IsBool   bool
+}
+
+// crudRoute represents a route registered for a resource.
+type crudRoute struct {
+	method  string
+	path    string
+	handler string
+}
+
+// DetectStackConfig reads the files of the project in `projectDir`
+// to find out the `StackConfig` it was created with.
+func DetectStackConfig(projectDir string) (StackConfig, error) {
+	var cfg StackConfig
+
+	if !fileExists(filepath.Join(projectDir, "go.mod")) {
+		return cfg, fmt.Errorf("'%s' is not a project directory, go.mod not found", projectDir)
+	}
+
+	// The web framework is imported by the server in `api/api.go`.
+	apiFile, err := parser.ParseFile(
+		token.NewFileSet(),
+		filepath.Join(projectDir, "api", "api.go"),
+		nil,
+		parser.ImportsOnly,
+	)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to detect the web framework: %v", err)
+	}
+	for _, imp := range apiFile.Imports {
+		for framework, path := range frameworkImports {
+			if imp.Path.Value == strconv.Quote(path) {
+				cfg.WebFramework = framework
+			}
+		}
+	}
+	if len(cfg.WebFramework) == 0 {
+		return cfg, fmt.Errorf("failed to detect the web framework from 'api/api.go'")
+	}
+
+	// Only projects rendering templates bundle their assets with a package.json.
+	deps, err := readPackageDeps(projectDir)
+	if err != nil {
+		return cfg, err
+	}
+	if deps == nil {
+		cfg.RenderingStrategy = "Seperate"
+	} else {
+		cfg.RenderingStrategy = "Templates"
+		cfg.CssStrategy = "Vanilla"
+		if version, ok := deps["tailwindcss"]; ok {
+			cfg.CssStrategy = "Tailwind4"
+			if strings.HasPrefix(strings.TrimLeft(version, "^~"), "3") {
+				cfg.CssStrategy = "Tailwind3"
+			}
+		}
+		if _, ok := deps["preline"]; ok {
+			cfg.UILibrary = "Preline"
+		}
+		if _, ok := deps["daisyui"]; ok {
+			cfg.UILibrary = "DaisyUI"
+		}
+	}
+
+	// An extra option is selected if any of its files exist, HTMX is only a dependency.
+	for _, opt := range config.ExtraOpts {
+		if opt == "HTMX" {
+			if _, ok := deps["htmx.org"]; ok {
+				cfg.ExtraOpts = append(cfg.ExtraOpts, opt)
+			}
+			continue
+		}
+		for _, file := range config.ProjectExtraFiles[opt] {
+			if fileExists(filepath.Join(projectDir, file)) {
+				cfg.ExtraOpts = append(cfg.ExtraOpts, opt)
+				break
+			}
+		}
+	}
+	for provider, file := range config.ProjectCIFiles {
+		if fileExists(filepath.Join(projectDir, file)) {
+			cfg.CIProvider = provider
+		}
+	}
+
+	return cfg, nil
+}
+
+// GeneratePage adds a page with the given `name` to the project in `projectDir`.
+//
+// It creates `web/<Name>.html`, adds the page handler to `api/handler.go` and
+// registers it in `RegisterRoutes` of `api/route.go`. The Go files are parsed
+// and only added to, it returns the paths of the written files.
+func GeneratePage(projectDir, name string, gen GenerateConfig) ([]string, error) {
+	cfg, err := DetectStackConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.RenderingStrategy != "Templates" {
+		return nil, fmt.Errorf("pages can only be generated with Templates rendering")
+	}
+
+	if !pageNameRe.MatchString(name) {
+		return nil, fmt.Errorf("invalid page name '%s', only letters and digits are allowed (eg. About)", name)
+	}
+	name = strings.ToUpper(name[:1]) + name[1:]
+
+	route := gen.Route
+	if len(route) == 0 {
+		route = "/" + toKebabCase(name)
+	}
+	if !strings.HasPrefix(route, "/") || strings.ContainsAny(route, " \t\"?#") {
+		return nil, fmt.Errorf("invalid route '%s', it must be a path starting with '/'", route)
+	}
+
+	var (
+		pagePath    = filepath.Join("web", name+".html")
+		handlerPath = filepath.Join("api", "handler.go")
+		routePath   = filepath.Join("api", "route.go")
+		handlerName = "handleGet" + name
+	)
+	if fileExists(filepath.Join(projectDir, pagePath)) {
+		return nil, fmt.Errorf("page '%s' already exists", pagePath)
+	}
+	exists, err := declaresName(filepath.Join(projectDir, "api"), handlerName)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("handler '%s' already exists in the api package", handlerName)
+	}
+
+	handlerSrc, err := addPageHandler(filepath.Join(projectDir, handlerPath), name, handlerName, cfg)
+	if err != nil {
+		return nil, err
+	}
+	routeSrc, err := addPageRoute(filepath.Join(projectDir, routePath), route, handlerName, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return writeGeneratedFiles(projectDir, []generatedFile{
+		{pagePath, generateNewPageContent(cfg)},
+		{handlerPath, handlerSrc},
+		{routePath, routeSrc},
+	})
+}
+
+// GenerateMiddleware adds a middleware with the given `name` to the project in `projectDir`.
+//
+// It creates `api/<name>.go` and registers the middleware in `registerGlobalMiddlewares`
+// of `api/api.go`. If a route group (eg. /admin) is given, every route under it in
+// `RegisterRoutes` is wrapped with the middleware instead. It returns the paths of the written files.
+func GenerateMiddleware(projectDir, name string, gen GenerateConfig) ([]string, error) {
+	cfg, err := DetectStackConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if !pageNameRe.MatchString(name) {
+		return nil, fmt.Errorf("invalid middleware name '%s', only letters and digits are allowed (eg. RequireAdmin)", name)
+	}
+	words := splitCamelCase(strings.ToUpper(name[:1]) + name[1:])
+	funcName := strings.ToLower(words[0]) + strings.Join(words[1:], "")
+	if token.IsKeyword(funcName) {
+		return nil, fmt.Errorf("invalid middleware name '%s', it's a Go keyword", name)
+	}
+
+	group := strings.TrimSuffix(gen.Group, "/")
+	if len(gen.Group) != 0 && !strings.HasPrefix(gen.Group, "/") {
+		return nil, fmt.Errorf("invalid group '%s', it must be a path starting with '/'", gen.Group)
+	}
+
+	var (
+		mwPath    = filepath.Join("api", strings.ToLower(funcName)+".go")
+		apiPath   = filepath.Join("api", "api.go")
+		routePath = filepath.Join("api", "route.go")
+	)
+	if fileExists(filepath.Join(projectDir, mwPath)) {
+		return nil, fmt.Errorf("file '%s' already exists", mwPath)
+	}
+	exists, err := declaresName(filepath.Join(projectDir, "api"), funcName)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("'%s' already exists in the api package", funcName)
+	}
+
+	var mw string
+	switch cfg.WebFramework {
+	case "Echo":
+		mw = echoMiddleware
+	case "Fiber":
+		mw = fiberMiddleware
+	case "Chi":
+		mw = chiMiddleware
+	}
+	mwSrc, err := format.Source([]byte(fmt.Sprintf(mw, funcName)))
+	if err != nil {
+		return nil, err
+	}
+
+	files := []generatedFile{{mwPath, mwSrc}}
+	if len(gen.Group) == 0 {
+		apiSrc, err := addGlobalMiddleware(filepath.Join(projectDir, apiPath), funcName)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, generatedFile{apiPath, apiSrc})
+	} else {
+		routeSrc, err := addGroupMiddleware(filepath.Join(projectDir, routePath), group, funcName, cfg)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, generatedFile{routePath, routeSrc})
+	}
+
+	return writeGeneratedFiles(projectDir, files)
+}
+
+// GenerateComponent adds a component (reusable partial) with the given `name`
+// to the project in `projectDir`.
+//
+// It creates `web/components/<Name>.html` which defines the `components/<Name>` template
+// and adds the components to the templates loaded in `api/api.go`, if they aren't already.
+// It returns the paths of the written files.
+func GenerateComponent(projectDir, name string) ([]string, error) {
+	cfg, err := DetectStackConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.RenderingStrategy != "Templates" {
+		return nil, fmt.Errorf("components can only be generated with Templates rendering")
+	}
+
+	if !pageNameRe.MatchString(name) {
+		return nil, fmt.Errorf("invalid component name '%s', only letters and digits are allowed (eg. Card)", name)
+	}
+	name = strings.ToUpper(name[:1]) + name[1:]
+
+	componentPath := filepath.Join("web", "components", name+".html")
+	if fileExists(filepath.Join(projectDir, componentPath)) {
+		return nil, fmt.Errorf("component '%s' already exists", componentPath)
+	}
+
+	files := []generatedFile{{componentPath, generateComponentContent(name, cfg)}}
+	if cfg.WebFramework != "Fiber" {
+		apiPath := filepath.Join("api", "api.go")
+		apiSrc, changed, err := addComponentsGlob(filepath.Join(projectDir, apiPath))
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			files = append(files, generatedFile{apiPath, apiSrc})
+		}
+	}
+
+	return writeGeneratedFiles(projectDir, files)
+}
+
+// GenerateCrud scaffolds the resource `name` with the given `fields` (eg. title:string done:bool)
+// in the project in `projectDir`.
+//
+// It creates the model with an in-memory store in its own package, the handlers
+// in `api/<resource>.go` and the pages (or JSON endpoints with a seperate client).
+// The routes are registered in `RegisterRoutes` of `api/route.go`, it returns the
+// paths of the written files.
+func GenerateCrud(projectDir, name string, fields []string) ([]string, error) {
+	cfg, err := DetectStackConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	modPath, err := readModulePath(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	res, err := newCrudResource(name, fields, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Refusing to overwrite anything which already exists.
+	targets := make([]string, 0, len(config.ProjectCrudFiles))
+	for target := range config.ProjectCrudFiles {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+
+	pages := map[string]string{}
+	if cfg.RenderingStrategy == "Templates" {
+		pages = map[string]string{
+			"list": filepath.Join("web", res.Plural+".html"),
+			"show": filepath.Join("web", res.Name+".html"),
+			"form": filepath.Join("web", res.Name+"Form.html"),
+		}
+	}
+	if fileExists(filepath.Join(projectDir, res.Package)) {
+		return nil, fmt.Errorf("'%s' already exists", res.Package)
+	}
+	paths := append([]string{}, targets...)
+	for _, page := range pages {
+		paths = append(paths, page)
+	}
+	for _, path := range paths {
+		path = strings.ReplaceAll(path, "resource", res.Package)
+		if fileExists(filepath.Join(projectDir, path)) {
+			return nil, fmt.Errorf("'%s' already exists", path)
+		}
+	}
+	for _, decl := range []string{res.Handler, "new" + res.Name + "Handler", "parse" + res.Name + "Form"} {
+		exists, err := declaresName(filepath.Join(projectDir, "api"), decl)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, fmt.Errorf("'%s' already exists in the api package", decl)
+		}
+	}
+
+	routePath := filepath.Join("api", "route.go")
+	routeSrc, err := addCrudRoutes(filepath.Join(projectDir, routePath), modPath, res, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := MakeProjectCtx(cfg, modPath)
+	ctx["Resource"] = res
+
+	files := make([]generatedFile, 0, len(targets)+len(pages)+1)
+	for _, target := range targets {
+		var (
+			path         = strings.ReplaceAll(target, "resource", res.Package)
+			templatePath = frameworkTemplatePath(config.ProjectCrudFiles[target], cfg.WebFramework)
+		)
+
+		processedTmpl, err := parseTemplate(path, templatePath, tmpls.GetCrudFiles())
+		if err != nil {
+			return nil, fmt.Errorf("template Parsing Error (pls report): %v", err)
+		}
+		var buf bytes.Buffer
+		if err := processedTmpl.template.Execute(&buf, ctx); err != nil {
+			return nil, fmt.Errorf("template Parsing Error (pls report): %v", err)
+		}
+		src, err := format.Source(buf.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("failed to format '%s' (pls report): %v", path, err)
+		}
+
+		files = append(files, generatedFile{path, src})
+	}
+	for _, page := range []string{"list", "show", "form"} {
+		if path, ok := pages[page]; ok {
+			files = append(files, generatedFile{path, generateCrudPageContent(page, res, cfg)})
+		}
+	}
+	files = append(files, generatedFile{routePath, routeSrc})
+
+	return writeGeneratedFiles(projectDir, files)
+}
+
+// newCrudResource validates the `name` and `fields` of a resource
+// and derives the names used in the generated code.
+func newCrudResource(name string, fields []string, cfg StackConfig) (crudResource, error) {
+	var res crudResource
+
+	if !pageNameRe.MatchString(name) {
+		return res, fmt.Errorf("invalid resource name '%s', only letters and digits are allowed (eg. Todo)", name)
+	}
+	res.Name = strings.ToUpper(name[:1]) + name[1:]
+	res.Plural = pluralize(res.Name)
+	res.Package = strings.ToLower(res.Name)
+	if token.IsKeyword(res.Package) || contains(reservedCrudPackages, res.Package) {
+		return res, fmt.Errorf("resource name '%s' can't be used as a Go package", name)
+	}
+
+	res.Human = strings.Join(splitCamelCase(res.Name), " ")
+	res.HumanPlural = strings.Join(splitCamelCase(res.Plural), " ")
+	res.Phrase = strings.ToLower(res.Human)
+	res.PluralPhrase = strings.ToLower(res.HumanPlural)
+	res.Handler = strings.ToLower(res.Name[:1]) + res.Name[1:] + "Handler"
+	res.Var = strings.ToLower(res.Plural[:1]) + res.Plural[1:]
+	res.Route = "/" + toKebabCase(res.Plural)
+	if cfg.RenderingStrategy == "Seperate" {
+		res.Route = "/api" + res.Route
+	}
+
+	// A resource without fields gets a name.
+	if len(fields) == 0 {
+		fields = []string{"name:string"}
+	}
+	for _, arg := range fields {
+		key, typ, _ := strings.Cut(arg, ":")
+		if len(typ) == 0 {
+			typ = "string"
+		}
+
+		goType, ok := crudFieldTypes[typ]
+		if !ok {
+			return res, fmt.Errorf(
+				"invalid type '%s' of field '%s', use one of: %s",
+				typ, key, strings.Join([]string{"string", "text", "int", "float", "bool"}, ", "),
+			)
+		}
+		if !crudFieldRe.MatchString(key) || contains(reservedCrudFields, key) {
+			return res, fmt.Errorf("invalid field '%s', use lowercase letters, digits and _ (eg. due_date)", key)
+		}
+		for _, field := range res.Fields {
+			if field.Key == key {
+				return res, fmt.Errorf("field '%s' is given more than once", key)
+			}
+		}
+
+		var words []string
+		for _, word := range strings.Split(key, "_") {
+			if len(word) != 0 {
+				words = append(words, word)
+			}
+		}
+		field := crudField{
+			Key:      key,
+			Phrase:   strings.Join(words, " "),
+			Type:     typ,
+			GoType:   goType,
+			IsString: goType == "string",
+			IsInt:    typ == "int",
+			IsFloat:  typ == "float",
+			IsBool:   typ == "bool",
+		}
+		for _, word := range words {
+			field.Name += strings.ToUpper(word[:1]) + word[1:]
+		}
+		field.Label = strings.ToUpper(field.Phrase[:1]) + field.Phrase[1:]
+
+		res.HasString = res.HasString || field.IsString
+		res.HasNumber = res.HasNumber || field.IsInt || field.IsFloat
+		res.Fields = append(res.Fields, field)
+	}
+
+	return res, nil
+}
+
+// routes returns the routes of the resource, forms can only
+// submit POST requests, JSON endpoints use the matching methods.
+func (res crudResource) routes(cfg StackConfig) []crudRoute {
+	item := res.Route + "/:id"
+	if cfg.WebFramework == "Chi" {
+		item = res.Route + "/{id}"
+	}
+
+	if cfg.RenderingStrategy == "Seperate" {
+		return []crudRoute{
+			{"GET", res.Route, "handleList"},
+			{"POST", res.Route, "handleCreate"},
+			{"GET", item, "handleShow"},
+			{"PUT", item, "handleUpdate"},
+			{"DELETE", item, "handleDelete"},
+		}
+	}
+
+	return []crudRoute{
+		{"GET", res.Route, "handleList"},
+		{"GET", res.Route + "/new", "handleNew"},
+		{"POST", res.Route, "handleCreate"},
+		{"GET", item, "handleShow"},
+		{"GET", item + "/edit", "handleEdit"},
+		{"POST", item, "handleUpdate"},
+		{"POST", item + "/delete", "handleDelete"},
+	}
+}
+
+// addCrudRoutes returns the source of the route file at `path` with the
+// routes of the resource registered at the end of `RegisterRoutes`.
+func addCrudRoutes(path, modPath string, res crudResource, cfg StackConfig) ([]byte, error) {
+	fset := token.NewFileSet()
+	src, file, err := parseGoFile(fset, path)
+	if err != nil {
+		return nil, err
+	}
+	fn, err := findRegisterRoutes(file, path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Refusing to register the routes twice or to redeclare the handler variable.
+	var conflict string
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			if isRoute(n, "", res.Route) {
+				conflict = fmt.Sprintf("route '%s' is already registered in '%s'", res.Route, path)
+			}
+		case *ast.Ident:
+			if n.Name == res.Var {
+				conflict = fmt.Sprintf("'%s' is already used in 'RegisterRoutes' of '%s'", res.Var, path)
+			}
+		}
+		return len(conflict) == 0
+	})
+	if len(conflict) != 0 {
+		return nil, errors.New(conflict)
+	}
+
+	router := fn.Type.Params.List[0].Names[0].Name
+	stmts := []string{
+		"// " + res.HumanPlural,
+		fmt.Sprintf("%s := new%sHandler(%s.NewMemoryStore())", res.Var, res.Name, res.Package),
+	}
+	for _, route := range res.routes(cfg) {
+		handler := res.Var + "." + route.handler
+		if cfg.WebFramework == "Chi" {
+			method := route.method[:1] + strings.ToLower(route.method[1:])
+			stmts = append(stmts, fmt.Sprintf("%s.%s(%s, %s)", router, method, strconv.Quote(route.path), handler))
+		} else {
+			stmts = append(stmts, fmt.Sprintf("%s.Add(%s, %s, %s)", router, strconv.Quote(route.method), strconv.Quote(route.path), handler))
+		}
+	}
+
+	edits := []codeEdit{{
+		offset: fset.Position(fn.Body.Rbrace).Offset,
+		text:   "\n\t" + strings.Join(stmts, "\n\t") + "\n",
+	}}
+	if edit, ok := importEdit(fset, file, modPath+"/"+res.Package); ok {
+		edits = append(edits, edit)
+	}
+
+	return applyEdits(path, src, edits)
+}
+
+// addPageHandler returns the source of the handler file at `path`
+// with the page handler appended and its imports added.
+func addPageHandler(path, name, handlerName string, cfg StackConfig) ([]byte, error) {
+	fset := token.NewFileSet()
+	src, file, err := parseGoFile(fset, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		handler string
+		imports []string
+	)
+	switch cfg.WebFramework {
+	case "Echo":
+		handler = fmt.Sprintf(echoPageHandler, handlerName, name)
+		imports = []string{"net/http", frameworkImports["Echo"]}
+	case "Fiber":
+		handler = fmt.Sprintf(fiberPageHandler, handlerName, name)
+		imports = []string{frameworkImports["Fiber"]}
+	case "Chi":
+		handler = fmt.Sprintf(chiPageHandler, handlerName, name)
+		imports = []string{"net/http"}
+	}
+
+	// gofmt collapses the extra blank line if the file ends with a newline.
+	edits := []codeEdit{{offset: len(src), text: "\n" + handler}}
+	for _, imp := range imports {
+		if edit, ok := importEdit(fset, file, imp); ok {
+			edits = append(edits, edit)
+		}
+	}
+
+	return applyEdits(path, src, edits)
+}
+
+// addPageRoute returns the source of the route file at `path` with the
+// page handler registered at the end of `RegisterRoutes`.
+func addPageRoute(path, route, handlerName string, cfg StackConfig) ([]byte, error) {
+	fset := token.NewFileSet()
+	src, file, err := parseGoFile(fset, path)
+	if err != nil {
+		return nil, err
+	}
+
+	fn, err := findRegisterRoutes(file, path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Refusing to register the same route twice.
+	registered := false
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok && isRoute(call, "GET", route) {
+			registered = true
+		}
+		return !registered
+	})
+	if registered {
+		return nil, fmt.Errorf("route 'GET %s' is already registered in '%s'", route, path)
+	}
+
+	router := fn.Type.Params.List[0].Names[0].Name
+	stmt := fmt.Sprintf("%s.Add(\"GET\", %s, %s)", router, strconv.Quote(route), handlerName)
+	if cfg.WebFramework == "Chi" {
+		stmt = fmt.Sprintf("%s.Get(%s, %s)", router, strconv.Quote(route), handlerName)
+	}
+
+	return applyEdits(path, src, []codeEdit{{
+		offset: fset.Position(fn.Body.Rbrace).Offset,
+		text:   fmt.Sprintf("\n\t// %s page\n\t%s\n", strings.TrimPrefix(handlerName, "handleGet"), stmt),
+	}})
+}
+
+// addGlobalMiddleware returns the source of the api file at `path` with the middleware
+// `funcName` used after the other middlewares in `registerGlobalMiddlewares`.
+func addGlobalMiddleware(path, funcName string) ([]byte, error) {
+	fset := token.NewFileSet()
+	src, file, err := parseGoFile(fset, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fn *ast.FuncDecl
+	for _, decl := range file.Decls {
+		if decl, ok := decl.(*ast.FuncDecl); ok && decl.Name.Name == "registerGlobalMiddlewares" && decl.Body != nil &&
+			decl.Type.Params.NumFields() != 0 && len(decl.Type.Params.List[0].Names) != 0 {
+			fn = decl
+			break
+		}
+	}
+	if fn == nil {
+		return nil, fmt.Errorf("failed to find the 'registerGlobalMiddlewares' method in '%s'", path)
+	}
+
+	app := fn.Type.Params.List[0].Names[0].Name
+	offset := fset.Position(fn.Body.Lbrace).Offset + 1
+	for _, stmt := range fn.Body.List {
+		expr, ok := stmt.(*ast.ExprStmt)
+		if !ok {
+			continue
+		}
+		if call, ok := expr.X.(*ast.CallExpr); ok && isMethodCall(call, app, "Use") {
+			offset = fset.Position(stmt.End()).Offset
+		}
+	}
+
+	return applyEdits(path, src, []codeEdit{{
+		offset: offset,
+		text:   fmt.Sprintf("\n\t%s.Use(%s)", app, funcName),
+	}})
+}
+
+// addGroupMiddleware returns the source of the route file at `path` with every route
+// under the `group` (eg. /admin) in `RegisterRoutes` wrapped with the middleware `funcName`.
+func addGroupMiddleware(path, group, funcName string, cfg StackConfig) ([]byte, error) {
+	fset := token.NewFileSet()
+	src, file, err := parseGoFile(fset, path)
+	if err != nil {
+		return nil, err
+	}
+
+	fn, err := findRegisterRoutes(file, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var edits []codeEdit
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		route, idx, ok := routeArg(call)
+		if !ok || (route != group && !strings.HasPrefix(route, group+"/")) || len(call.Args) <= idx+1 {
+			return true
+		}
+
+		handler := call.Args[idx+1]
+		switch cfg.WebFramework {
+		case "Echo":
+			// Echo routes take a single handler, it's wrapped.
+			edits = append(edits,
+				codeEdit{offset: fset.Position(handler.Pos()).Offset, text: funcName + "("},
+				codeEdit{offset: fset.Position(handler.End()).Offset, text: ")"},
+			)
+		case "Fiber":
+			// Fiber runs the handlers in order.
+			edits = append(edits, codeEdit{offset: fset.Position(handler.Pos()).Offset, text: funcName + ", "})
+		case "Chi":
+			edits = append(edits, codeEdit{
+				offset: fset.Position(call.Fun.(*ast.SelectorExpr).X.End()).Offset,
+				text:   fmt.Sprintf(".With(%s)", funcName),
+			})
+		}
+		return true
+	})
+	if len(edits) == 0 {
+		return nil, fmt.Errorf("no routes under '%s' are registered in '%s'", group, path)
+	}
+
+	return applyEdits(path, src, edits)
+}
+
+// addComponentsGlob returns the source of the api file at `path` with the
+// components loaded by `LoadTemplates`, false is returned if they already are.
+func addComponentsGlob(path string) ([]byte, bool, error) {
+	fset := token.NewFileSet()
+	src, file, err := parseGoFile(fset, path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var (
+		load    *ast.CallExpr
+		matched bool
+	)
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "LoadTemplates" && len(call.Args) != 0 {
+			load = call
+			for _, arg := range call.Args {
+				matched = matched || isStringLit(arg, componentsGlob)
+			}
+		}
+		return true
+	})
+	if load == nil {
+		return nil, false, fmt.Errorf("failed to find the 'LoadTemplates' call in '%s'", path)
+	}
+	if matched {
+		return nil, false, nil
+	}
+
+	result, err := applyEdits(path, src, []codeEdit{{
+		offset: fset.Position(load.Args[len(load.Args)-1].End()).Offset,
+		text:   ", " + strconv.Quote(componentsGlob),
+	}})
+
+	return result, err == nil, err
+}
+
+// findRegisterRoutes returns the `RegisterRoutes` method of the route `file` at `path`.
+func findRegisterRoutes(file *ast.File, path string) (*ast.FuncDecl, error) {
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || fn.Name.Name != "RegisterRoutes" || fn.Body == nil {
+			continue
+		}
+		// The router parameter is needed for registering the routes.
+		if fn.Type.Params.NumFields() != 0 && len(fn.Type.Params.List[0].Names) != 0 {
+			return fn, nil
+		}
+	}
+
+	return nil, fmt.Errorf("failed to find the 'RegisterRoutes' method in '%s'", path)
+}
+
+// isRoute checks if the `call` registers the `route` for the HTTP `method`,
+// eg. router.Add("GET", "/about", ...) or router.Get("/about", ...).
+// An empty `method` matches the route registered for any method.
+func isRoute(call *ast.CallExpr, method, route string) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+
+	args := call.Args
+	switch strings.ToUpper(sel.Sel.Name) {
+	case "ADD":
+		if len(args) < 2 {
+			return false
+		}
+		if lit, ok := args[0].(*ast.BasicLit); !ok || (len(method) != 0 && !isStringLit(lit, method)) {
+			return false
+		}
+	case "GET", "POST", "PUT", "PATCH", "DELETE":
+		if len(method) != 0 && !strings.EqualFold(sel.Sel.Name, method) {
+			return false
+		}
+	default:
+		return false
+	}
+
+	r, _, ok := routeArg(call)
+	return ok && r == route
+}
+
+// routeArg returns the route registered by the `call` and the index of its argument,
+// eg. router.Add("GET", "/about", ...) or router.Get("/about", ...).
+func routeArg(call *ast.CallExpr) (string, int, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", 0, false
+	}
+
+	idx := 0
+	switch strings.ToUpper(sel.Sel.Name) {
+	case "ADD":
+		idx = 1
+	case "GET", "POST", "PUT", "PATCH", "DELETE":
+	default:
+		return "", 0, false
+	}
+	if len(call.Args) <= idx {
+		return "", 0, false
+	}
+
+	lit, ok := call.Args[idx].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", 0, false
+	}
+	route, err := strconv.Unquote(lit.Value)
+
+	return route, idx, err == nil
+}
+
+// isMethodCall checks if the `call` calls the `method` on the variable `recv` (eg. e.Use(...)).
+func isMethodCall(call *ast.CallExpr, recv, method string) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != method {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+
+	return ok && ident.Name == recv
+}
+
+// isStringLit checks if the `expr` is a string literal with the value `v`.
+func isStringLit(expr ast.Expr, v string) bool {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return false
+	}
+	s, err := strconv.Unquote(lit.Value)
+
+	return err == nil && s == v
+}
+
+// declaresName checks if any Go file of the package in `dir` declares a function,
+// type, variable or constant with the given `name` or imports a package by it.
+// The tests of the package are included, as they share its scope.
+func declaresName(dir, name string) (bool, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return false, err
+	}
+
+	for _, path := range paths {
+		_, file, err := parseGoFile(token.NewFileSet(), path)
+		if err != nil {
+			return false, err
+		}
+		// External test packages (eg. api_test) have their own scope.
+		if strings.HasSuffix(file.Name.Name, "_test") {
+			continue
+		}
+		for _, imp := range file.Imports {
+			if importName(imp) == name {
+				return true, nil
+			}
+		}
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Recv == nil && decl.Name.Name == name {
+					return true, nil
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						if spec.Name.Name == name {
+							return true, nil
+						}
+					case *ast.ValueSpec:
+						for _, ident := range spec.Names {
+							if ident.Name == name {
+								return true, nil
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// importName returns the name a package is used by in the file importing it,
+// the last element of its path without the major version (eg. echo/v4 -> echo).
+func importName(imp *ast.ImportSpec) string {
+	if imp.Name != nil {
+		return imp.Name.Name
+	}
+
+	path, _ := strconv.Unquote(imp.Path.Value)
+	parts := strings.Split(path, "/")
+	name := parts[len(parts)-1]
+	if len(parts) > 1 && majorVersionRe.MatchString(name) {
+		name = parts[len(parts)-2]
+	}
+
+	return name
+}
+
+// importEdit returns the edit adding the import `path` to the `file`,
+// false is returned if it's already imported.
+//
+// The import is added after the last one of its group (eg. the standard
+// library or the same module), so gofmt keeps the groups apart.
+func importEdit(fset *token.FileSet, file *ast.File, path string) (codeEdit, bool) {
+	var last *ast.ImportSpec
+	for _, imp := range file.Imports {
+		if imp.Path.Value == strconv.Quote(path) {
+			return codeEdit{}, false
+		}
+		if impPath, err := strconv.Unquote(imp.Path.Value); err == nil && importGroup(impPath) == importGroup(path) {
+			last = imp
+		}
+	}
+	if last != nil {
+		return codeEdit{
+			offset: fset.Position(last.End()).Offset,
+			text:   fmt.Sprintf("\n\t%s", strconv.Quote(path)),
+		}, true
+	}
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		if gen.Lparen.IsValid() {
+			return codeEdit{
+				offset: fset.Position(gen.Rparen).Offset,
+				text:   fmt.Sprintf("\t%s\n", strconv.Quote(path)),
+			}, true
+		}
+		return codeEdit{
+			offset: fset.Position(gen.End()).Offset,
+			text:   fmt.Sprintf("\nimport %s", strconv.Quote(path)),
+		}, true
+	}
+
+	return codeEdit{
+		offset: fset.Position(file.Name.End()).Offset,
+		text:   fmt.Sprintf("\n\nimport %s", strconv.Quote(path)),
+	}, true
+}
+
+// importGroup returns the group of an import `path`, the standard library
+// or the domain of the module (eg. github.com).
+func importGroup(path string) string {
+	first, _, _ := strings.Cut(path, "/")
+	if !strings.Contains(first, ".") {
+		return "std"
+	}
+
+	return first
+}
+
+// applyEdits inserts the `edits` into the `src` of the Go file
+// at `path` and returns the gofmt formatted result.
+func applyEdits(path string, src []byte, edits []codeEdit) ([]byte, error) {
+	// Inserting from the end, so the offsets of earlier edits stay valid.
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].offset > edits[j].offset
+	})
+
+	result := string(src)
+	for _, edit := range edits {
+		result = result[:edit.offset] + edit.text + result[edit.offset:]
+	}
+
+	formatted, err := format.Source([]byte(result))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format '%s': %v", path, err)
+	}
+
+	return formatted, nil
+}
+
+// writeGeneratedFiles writes the `files` to the project in `projectDir`.
+// It's only called once every file could be generated, it returns the written paths.
+func writeGeneratedFiles(projectDir string, files []generatedFile) ([]string, error) {
+	written := make([]string, 0, len(files))
+	for _, file := range files {
+		if err := writeRawTemplateFile(filepath.Join(projectDir, file.path), file.content); err != nil {
+			return written, fmt.Errorf("failed to write '%s' due to %v", file.path, err)
+		}
+		written = append(written, file.path)
+	}
+
+	return written, nil
+}
+
+// parseGoFile reads and parses the Go file at `path`.
+func parseGoFile(fset *token.FileSet, path string) ([]byte, *ast.File, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse '%s': %v", path, err)
+	}
+
+	return src, file, nil
+}
+
+// readPackageDeps returns all the dependencies from the package.json
+// in `projectDir`, nil is returned if the project doesn't have one.
+func readPackageDeps(projectDir string) (map[string]string, error) {
+	b, err := os.ReadFile(filepath.Join(projectDir, "package.json"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var pkg struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(b, &pkg); err != nil {
+		return nil, fmt.Errorf("failed to read package.json: %v", err)
+	}
+
+	deps := make(map[string]string)
+	for name, version := range pkg.DevDependencies {
+		deps[name] = version
+	}
+	for name, version := range pkg.Dependencies {
+		deps[name] = version
+	}
+
+	return deps, nil
+}
+
+// readModulePath returns the module path from the go.mod in `projectDir`.
+func readModulePath(projectDir string) (string, error) {
+	b, err := os.ReadFile(filepath.Join(projectDir, "go.mod"))
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(string(b), "\n") {
+		if path, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(path), `"`), nil
+		}
+	}
+
+	return "", fmt.Errorf("module path not found in go.mod")
+}
+
+// splitCamelCase splits a PascalCase `name` into its words (eg. ContactUs -> Contact, Us).
+func splitCamelCase(name string) []string {
+	var (
+		words []string
+		start int
+	)
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := rune(name[i-1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) {
+				words = append(words, name[start:i])
+				start = i
+			}
+		}
+	}
+
+	return append(words, name[start:])
+}
+
+// toKebabCase converts a PascalCase `name` to kebab-case (eg. ContactUs -> contact-us).
+func toKebabCase(name string) string {
+	return strings.ToLower(strings.Join(splitCamelCase(name), "-"))
+}
+
+// pluralize returns the plural of an english `name` (eg. Todo -> Todos, Category -> Categories).
+func pluralize(name string) string {
+	switch {
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsRune("aeiouAEIOU", rune(name[len(name)-2])):
+		return name[:len(name)-1] + "ies"
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"), strings.HasSuffix(name, "z"),
+		strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	default:
+		return name + "s"
+	}
+}
+
+// fileExists checks if a file or directory exists at `path`.
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
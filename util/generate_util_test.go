@@ -0,0 +1,371 @@
+package util
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// createTestProject creates a project with the `cfg` in a temp dir.
+func createTestProject(t *testing.T, cfg StackConfig) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := CreateProject(dir, cfg, MakeProjectCtx(cfg, "example.com/app")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return dir
+}
+
+func TestDetectStackConfig(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	cfg := StackConfig{
+		WebFramework:      "Fiber",
+		CssStrategy:       "Tailwind3",
+		UILibrary:         "Preline",
+		RenderingStrategy: "Templates",
+		ExtraOpts:         []string{"HTMX", "Dockerfile", "CI", "Security"},
+		CIProvider:        "GitLab",
+	}
+	detected, err := DetectStackConfig(createTestProject(t, cfg))
+	a.NoError(err)
+	a.Equal(cfg, detected)
+
+	cfg = StackConfig{
+		WebFramework:      "Chi",
+		RenderingStrategy: "Seperate",
+	}
+	detected, err = DetectStackConfig(createTestProject(t, cfg))
+	a.NoError(err)
+	a.Equal(cfg, detected)
+
+	// Not a project directory.
+	_, err = DetectStackConfig(t.TempDir())
+	a.Error(err)
+}
+
+func TestGeneratePage(t *testing.T) {
+	t.Parallel()
+
+	for _, framework := range []string{"Echo", "Fiber", "Chi"} {
+		t.Run(framework, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+
+			dir := createTestProject(t, StackConfig{
+				WebFramework:      framework,
+				CssStrategy:       "Vanilla",
+				RenderingStrategy: "Templates",
+			})
+
+			files, err := GeneratePage(dir, "ContactUs", GenerateConfig{})
+			a.NoError(err)
+			a.Equal([]string{
+				filepath.Join("web", "ContactUs.html"),
+				filepath.Join("api", "handler.go"),
+				filepath.Join("api", "route.go"),
+			}, files)
+
+			page, err := os.ReadFile(filepath.Join(dir, "web", "ContactUs.html"))
+			a.NoError(err)
+			a.Contains(string(page), "{{ .Ctx.Title }}")
+
+			// The handler and route are added to the existing files.
+			handler, err := os.ReadFile(filepath.Join(dir, "api", "handler.go"))
+			a.NoError(err)
+			a.Contains(string(handler), "func handleGetHome(")
+			a.Contains(string(handler), "func handleGetContactUs(")
+
+			route, err := os.ReadFile(filepath.Join(dir, "api", "route.go"))
+			a.NoError(err)
+			a.Contains(string(route), "handleGetHome)")
+			a.Contains(string(route), `"/contact-us", handleGetContactUs)`)
+
+			for _, file := range []string{"handler.go", "route.go"} {
+				_, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, "api", file), nil, 0)
+				a.NoError(err)
+			}
+
+			// A page, handler or route can't be generated twice.
+			_, err = GeneratePage(dir, "ContactUs", GenerateConfig{Route: "/contact"})
+			a.Error(err)
+			_, err = GeneratePage(dir, "Contact", GenerateConfig{Route: "/contact-us"})
+			a.Error(err)
+			a.NoFileExists(filepath.Join(dir, "web", "Contact.html"))
+
+			// Invalid names and routes.
+			_, err = GeneratePage(dir, "about-us", GenerateConfig{})
+			a.Error(err)
+			_, err = GeneratePage(dir, "About", GenerateConfig{Route: "about"})
+			a.Error(err)
+		})
+	}
+
+	// Pages are only supported with templates.
+	dir := createTestProject(t, StackConfig{
+		WebFramework:      "Echo",
+		RenderingStrategy: "Seperate",
+	})
+	_, err := GeneratePage(dir, "About", GenerateConfig{})
+	assert.Error(t, err)
+}
+
+func TestGenerateCrud(t *testing.T) {
+	t.Parallel()
+
+	for _, framework := range []string{"Echo", "Fiber", "Chi"} {
+		t.Run(framework, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+
+			dir := createTestProject(t, StackConfig{
+				WebFramework:      framework,
+				CssStrategy:       "Tailwind4",
+				RenderingStrategy: "Templates",
+				ExtraOpts:         []string{"HTMX", "Security"},
+			})
+
+			files, err := GenerateCrud(dir, "BlogPost", []string{"title:string", "body:text", "likes:int", "published:bool"})
+			a.NoError(err)
+			a.Equal([]string{
+				filepath.Join("api", "blogpost.go"),
+				filepath.Join("api", "blogpost_test.go"),
+				filepath.Join("blogpost", "blogpost.go"),
+				filepath.Join("blogpost", "store.go"),
+				filepath.Join("blogpost", "store_test.go"),
+				filepath.Join("web", "BlogPosts.html"),
+				filepath.Join("web", "BlogPost.html"),
+				filepath.Join("web", "BlogPostForm.html"),
+				filepath.Join("api", "route.go"),
+			}, files)
+
+			for _, file := range files {
+				if filepath.Ext(file) == ".go" {
+					_, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, file), nil, 0)
+					a.NoError(err, file)
+				}
+			}
+
+			model, err := os.ReadFile(filepath.Join(dir, "blogpost", "blogpost.go"))
+			a.NoError(err)
+			a.Contains(string(model), "Likes     int    `json:\"likes\"`")
+
+			// The routes and the import of the resource are added to the existing ones.
+			route, err := os.ReadFile(filepath.Join(dir, "api", "route.go"))
+			a.NoError(err)
+			a.Contains(string(route), "handleGetHome)")
+			a.Contains(string(route), "blogPosts := newBlogPostHandler(blogpost.NewMemoryStore())")
+			a.Contains(string(route), `"/blog-posts/new", blogPosts.handleNew)`)
+			a.Contains(string(route), `"example.com/app/blogpost"`)
+
+			// The delete forms are inside of range, the token is taken from the root.
+			list, err := os.ReadFile(filepath.Join(dir, "web", "BlogPosts.html"))
+			a.NoError(err)
+			a.Contains(string(list), "{{ csrfField $.CSRF }}")
+			a.Contains(string(list), `hx-target="closest tr"`)
+
+			// A resource can't be generated twice.
+			_, err = GenerateCrud(dir, "BlogPost", nil)
+			a.Error(err)
+		})
+	}
+
+	// A seperate client gets JSON endpoints without pages.
+	dir := createTestProject(t, StackConfig{
+		WebFramework:      "Chi",
+		RenderingStrategy: "Seperate",
+	})
+	files, err := GenerateCrud(dir, "Category", nil)
+	assert.NoError(t, err)
+	assert.Len(t, files, 6)
+	route, err := os.ReadFile(filepath.Join(dir, "api", "route.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(route), `router.Delete("/api/categories/{id}", categories.handleDelete)`)
+}
+
+func TestGenerateMiddleware(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		framework string
+		global    string
+		group     string
+	}{
+		{"Echo", "e.Use(requestTimer)", `router.Add("GET", "/todos", requireAdmin(todos.handleList))`},
+		{"Fiber", "app.Use(requestTimer)", `router.Add("GET", "/todos", requireAdmin, todos.handleList)`},
+		{"Chi", "mux.Use(requestTimer)", `router.With(requireAdmin).Get("/todos", todos.handleList)`},
+	} {
+		t.Run(tc.framework, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+
+			dir := createTestProject(t, StackConfig{
+				WebFramework:      tc.framework,
+				CssStrategy:       "Vanilla",
+				RenderingStrategy: "Templates",
+			})
+			_, err := GenerateCrud(dir, "Todo", nil)
+			a.NoError(err)
+
+			files, err := GenerateMiddleware(dir, "RequestTimer", GenerateConfig{})
+			a.NoError(err)
+			a.Equal([]string{filepath.Join("api", "requesttimer.go"), filepath.Join("api", "api.go")}, files)
+
+			api, err := os.ReadFile(filepath.Join(dir, "api", "api.go"))
+			a.NoError(err)
+			a.Contains(string(api), tc.global)
+
+			// Only the routes under the group are wrapped.
+			files, err = GenerateMiddleware(dir, "RequireAdmin", GenerateConfig{Group: "/todos/"})
+			a.NoError(err)
+			a.Equal([]string{filepath.Join("api", "requireadmin.go"), filepath.Join("api", "route.go")}, files)
+
+			route, err := os.ReadFile(filepath.Join(dir, "api", "route.go"))
+			a.NoError(err)
+			a.Contains(string(route), tc.group)
+			a.NotContains(string(route), `"/", requireAdmin`)
+			a.NotContains(string(route), `With(requireAdmin).Get("/",`)
+
+			for _, file := range []string{"api.go", "route.go", "requesttimer.go", "requireadmin.go"} {
+				_, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, "api", file), nil, 0)
+				a.NoError(err, file)
+			}
+
+			// Names which are already used, keywords and groups without routes.
+			_, err = GenerateMiddleware(dir, "RequestTimer", GenerateConfig{})
+			a.Error(err)
+			_, err = GenerateMiddleware(dir, "Config", GenerateConfig{})
+			a.Error(err)
+			_, err = GenerateMiddleware(dir, "Func", GenerateConfig{})
+			a.Error(err)
+			_, err = GenerateMiddleware(dir, "RequireUser", GenerateConfig{Group: "/admin"})
+			a.Error(err)
+			a.NoFileExists(filepath.Join(dir, "api", "requireuser.go"))
+		})
+	}
+}
+
+func TestGenerateComponent(t *testing.T) {
+	t.Parallel()
+
+	for _, framework := range []string{"Echo", "Fiber", "Chi"} {
+		t.Run(framework, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+
+			dir := createTestProject(t, StackConfig{
+				WebFramework:      framework,
+				CssStrategy:       "Tailwind4",
+				RenderingStrategy: "Templates",
+			})
+
+			files, err := GenerateComponent(dir, "card")
+			a.NoError(err)
+
+			component, err := os.ReadFile(filepath.Join(dir, "web", "components", "Card.html"))
+			a.NoError(err)
+			a.Contains(string(component), `{{ define "components/Card" }}`)
+
+			// Fiber loads every template in the web directory.
+			api, err := os.ReadFile(filepath.Join(dir, "api", "api.go"))
+			a.NoError(err)
+			if framework == "Fiber" {
+				a.Len(files, 1)
+				a.NotContains(string(api), componentsGlob)
+			} else {
+				a.Equal([]string{filepath.Join("web", "components", "Card.html"), filepath.Join("api", "api.go")}, files)
+				a.Contains(string(api), `.html", `+strconv.Quote(componentsGlob)+")")
+			}
+
+			// The glob is only added once.
+			files, err = GenerateComponent(dir, "Alert")
+			a.NoError(err)
+			a.Equal([]string{filepath.Join("web", "components", "Alert.html")}, files)
+
+			_, err = GenerateComponent(dir, "Card")
+			a.Error(err)
+			_, err = GenerateComponent(dir, "my-card")
+			a.Error(err)
+		})
+	}
+
+	// Components are only supported with templates.
+	dir := createTestProject(t, StackConfig{
+		WebFramework:      "Fiber",
+		RenderingStrategy: "Seperate",
+	})
+	_, err := GenerateComponent(dir, "Card")
+	assert.Error(t, err)
+}
+
+func TestNewCrudResource(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	cfg := StackConfig{WebFramework: "Echo", RenderingStrategy: "Templates"}
+
+	res, err := newCrudResource("todo", []string{"title", "due_date:string", "done:bool"}, cfg)
+	a.NoError(err)
+	a.Equal("Todo", res.Name)
+	a.Equal("todo", res.Package)
+	a.Equal("/todos", res.Route)
+	a.Len(res.Fields, 3)
+	a.Equal("DueDate", res.Fields[1].Name)
+	a.Equal("Due date", res.Fields[1].Label)
+	a.True(res.HasString)
+	a.False(res.HasNumber)
+
+	// A resource without fields gets a name.
+	res, err = newCrudResource("Todo", nil, cfg)
+	a.NoError(err)
+	a.Equal("Name", res.Fields[0].Name)
+
+	// Invalid names, fields and types.
+	for _, tc := range []struct {
+		name   string
+		fields []string
+	}{
+		{"blog-post", nil},
+		{"Http", nil},
+		{"Func", nil},
+		{"Todo", []string{"Title:string"}},
+		{"Todo", []string{"id:string"}},
+		{"Todo", []string{"title:date"}},
+		{"Todo", []string{"title", "title:text"}},
+	} {
+		_, err := newCrudResource(tc.name, tc.fields, cfg)
+		a.Error(err, tc)
+	}
+}
+
+func TestPluralize(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	a.Equal("Todos", pluralize("Todo"))
+	a.Equal("Categories", pluralize("Category"))
+	a.Equal("Days", pluralize("Day"))
+	a.Equal("Boxes", pluralize("Box"))
+	a.Equal("Matches", pluralize("Match"))
+}
+
+func TestToKebabCase(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	a.Equal("about", toKebabCase("About"))
+	a.Equal("contact-us", toKebabCase("ContactUs"))
+	a.Equal("faq", toKebabCase("FAQ"))
+	a.Equal("page2-info", toKebabCase("Page2Info"))
+}
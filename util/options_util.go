@@ -0,0 +1,101 @@
+package util
+
+import (
+	"sort"
+
+	"github.com/nilotpaul/gospur/config"
+)
+
+// optionsSchemaVersion is bumped on breaking changes of the `OptionsCatalog`,
+// fields are only added otherwise.
+const optionsSchemaVersion = 1
+
+// OptionsCatalog represents every option of `gospur init`, it's printed by `gospur options`.
+type OptionsCatalog struct {
+	SchemaVersion int          `json:"schemaVersion" yaml:"schemaVersion"`
+	Options       []OptionFlag `json:"options" yaml:"options"`
+}
+
+// OptionFlag represents a flag of `gospur init` and the values it accepts.
+type OptionFlag struct {
+	// Flag is the name of the flag, eg. framework for --framework.
+	Flag string `json:"flag" yaml:"flag"`
+	// Name is what the option is, eg. Web Framework.
+	Name string `json:"name" yaml:"name"`
+	// Multiple is true if the flag takes a comma separated list of values.
+	Multiple bool          `json:"multiple" yaml:"multiple"`
+	Values   []OptionValue `json:"values" yaml:"values"`
+	// Requires are the values other flags must have for this flag to be used.
+	Requires map[string][]string `json:"requires,omitempty" yaml:"requires,omitempty"`
+}
+
+// OptionValue represents a value of an `OptionFlag`.
+type OptionValue struct {
+	// Name is the name shown in the prompts, eg. Seperate Client (SPA).
+	Name string `json:"name" yaml:"name"`
+	// Value is what's passed to the flag, eg. Seperate.
+	Value string `json:"value" yaml:"value"`
+	// Requires maps the flags to the values this value can be used with, one of them must
+	// be set (or be part of a `Multiple` flag), eg. {"styling": ["Tailwind3", "Tailwind4"]}.
+	Requires map[string][]string `json:"requires,omitempty" yaml:"requires,omitempty"`
+}
+
+// extraRequires are the values the extras can be used with, as validated by `ValidateStackConfig`.
+var extraRequires = map[string]map[string][]string{
+	"Auth":    {"render": {"Templates"}},
+	"I18n":    {"render": {"Templates"}},
+	"OpenAPI": {"render": {"Seperate"}},
+	"Compose": {"extra": {"Dockerfile"}},
+}
+
+// GetOptionsCatalog returns every option of `gospur init` with their constraints,
+// the values are in the order they're declared in (sorted if they're a map).
+func GetOptionsCatalog() OptionsCatalog {
+	// Styling and UI libraries are only used by Templates rendering.
+	templatesOnly := map[string][]string{"render": {"Templates"}}
+
+	renderNames := GetMapKeys(config.RenderingStrategy)
+	sort.Strings(renderNames)
+	render := make([]OptionValue, 0, len(renderNames))
+	for _, name := range renderNames {
+		render = append(render, OptionValue{Name: name, Value: config.RenderingStrategy[name]})
+	}
+
+	libs := GetMapKeys(config.UILibraryOpts)
+	sort.Strings(libs)
+	ui := make([]OptionValue, 0, len(libs))
+	for _, lib := range libs {
+		ui = append(ui, OptionValue{
+			Name:     lib,
+			Value:    lib,
+			Requires: map[string][]string{"styling": config.UILibraryOpts[lib]},
+		})
+	}
+
+	extras := optionValues(config.ExtraOpts)
+	for i := range extras {
+		extras[i].Requires = extraRequires[extras[i].Value]
+	}
+
+	return OptionsCatalog{
+		SchemaVersion: optionsSchemaVersion,
+		Options: []OptionFlag{
+			{Flag: "framework", Name: "Web Framework", Values: optionValues(config.WebFrameworkOpts)},
+			{Flag: "render", Name: "Rendering Strategy", Values: render},
+			{Flag: "styling", Name: "CSS Strategy", Values: optionValues(config.CssStrategyOpts), Requires: templatesOnly},
+			{Flag: "ui", Name: "UI Library", Values: ui, Requires: templatesOnly},
+			{Flag: "extra", Name: "Extras", Multiple: true, Values: extras},
+			{Flag: "ci", Name: "CI Provider", Values: optionValues(config.CIProviderOpts), Requires: map[string][]string{"extra": {"CI"}}},
+		},
+	}
+}
+
+// optionValues returns the `opts` as values whose name is the value itself.
+func optionValues(opts []string) []OptionValue {
+	values := make([]OptionValue, 0, len(opts))
+	for _, opt := range opts {
+		values = append(values, OptionValue{Name: opt, Value: opt})
+	}
+
+	return values
+}
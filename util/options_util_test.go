@@ -0,0 +1,50 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/nilotpaul/gospur/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOptionsCatalog(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	catalog := GetOptionsCatalog()
+	a.Equal(1, catalog.SchemaVersion)
+
+	flags := make(map[string]OptionFlag)
+	for _, opt := range catalog.Options {
+		flags[opt.Flag] = opt
+	}
+	a.Len(flags, 6)
+	a.Contains(flags["render"].Values, OptionValue{Name: "Seperate Client (SPA)", Value: "Seperate"})
+	a.Len(flags["framework"].Values, len(config.WebFrameworkOpts))
+	a.True(flags["extra"].Multiple)
+	a.Equal(map[string][]string{"extra": {"CI"}}, flags["ci"].Requires)
+	for _, v := range flags["ui"].Values {
+		a.Equal(config.UILibraryOpts[v.Value], v.Requires["styling"])
+	}
+
+	// The constraints of the extras match the validation of init.
+	for _, extra := range flags["extra"].Values {
+		for _, render := range GetRenderingOpts(true) {
+			cfg := StackConfig{
+				WebFramework:      "Echo",
+				RenderingStrategy: render,
+				ExtraOpts:         []string{extra.Value},
+			}
+			allowed := true
+			for flag, values := range extra.Requires {
+				switch flag {
+				case "render":
+					allowed = allowed && contains(values, render)
+				case "extra":
+					allowed = false
+				}
+			}
+			a.Equal(allowed, ValidateStackConfig(cfg) == nil, "%s with %s", extra.Value, render)
+		}
+	}
+}
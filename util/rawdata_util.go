@@ -2,11 +2,15 @@ package util
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/yosssi/gohtml"
 )
 
+// postFormRe matches the opening tag of forms which are submitted via POST.
+var postFormRe = regexp.MustCompile(`(<form method="post"[^>]*>)`)
+
 const (
 	basicHomeBodyExampleHTML = `
 <body class="container">
@@ -18,7 +22,7 @@ const (
         height="500"
         width="500"
       />
-      <p>{{ .Ctx.Desc }}</p>
+      <p>{{ .Ctx.Desc }}</p>%s
     </div>
 </body>`
 	tailwindHomeBodyExampleHTML = `
@@ -33,10 +37,40 @@ const (
         height="500"
         width="500"
       />
-      <p class="text-lg font-medium">{{ .Ctx.Desc }}</p>
+      <p class="text-lg font-medium">{{ .Ctx.Desc }}</p>%s
     </div>
 </body>`
 
+	basicWebSocketDemoHTML = `
+      <div id="ws-chat"%[1]s>
+        <div id="ws-messages"></div>
+        <form id="ws-form" class="form"%[2]s>
+          <input type="text" name="message" placeholder="Say hi to every open tab" autocomplete="off" required />
+          <button type="submit">Send</button>
+        </form>
+      </div>`
+	tailwindWebSocketDemoHTML = `
+      <div id="ws-chat" class="flex flex-col gap-y-4 w-full"%[1]s>
+        <div id="ws-messages" class="flex flex-col gap-y-1"></div>
+        <form id="ws-form" class="flex gap-x-2"%[2]s>
+          <input type="text" name="message" placeholder="Say hi to every open tab" autocomplete="off" required class="flex-1 rounded-md border-gray-300" />
+          <button type="submit" class="rounded-md bg-blue-600 px-4 py-2 font-medium text-white hover:bg-blue-700">Send</button>
+        </form>
+      </div>`
+
+	basicUploadFormHTML = `
+      <form method="post" action="/upload" enctype="multipart/form-data" class="form"%s>
+        <p id="upload-status"></p>
+        <input type="file" name="file" accept="image/png,image/jpeg,image/gif,image/webp,application/pdf" required />
+        <button type="submit">Upload</button>
+      </form>`
+	tailwindUploadFormHTML = `
+      <form method="post" action="/upload" enctype="multipart/form-data" class="flex flex-col gap-y-4 w-full"%s>
+        <p id="upload-status" class="text-sm"></p>
+        <input type="file" name="file" accept="image/png,image/jpeg,image/gif,image/webp,application/pdf" required class="rounded-md border-gray-300" />
+        <button type="submit" class="rounded-md bg-blue-600 px-4 py-2 font-medium text-white hover:bg-blue-700">Upload</button>
+      </form>`
+
 	basicErrorBodyExampleHTML = `
 <body class="container">
   <h1>{{ .Ctx.FullError }}</h1>
@@ -45,6 +79,107 @@ const (
 <body class="flex items-center justify-center">
     <h1 class="text-4xl my-4 font-bold">{{ .Ctx.FullError }}</h1>
 </body>`
+
+	basicI18nErrorBodyExampleHTML = `
+<body class="container">
+  <h1>{{ t .Locale "error.title" }}</h1>
+  <p>{{ .Ctx.FullError }}</p>
+</body>`
+	tailwindI18nErrorBodyExampleHTML = `
+<body class="flex items-center justify-center">
+    <div class="flex flex-col items-center">
+      <h1 class="text-4xl my-4 font-bold">{{ t .Locale "error.title" }}</h1>
+      <p class="text-lg">{{ .Ctx.FullError }}</p>
+    </div>
+</body>`
+
+	basicAuthFormBodyExampleHTML = `
+<body class="container">
+    <div>
+      <h1>%[1]s</h1>
+      <form method="post" action="%[2]s" class="form"%[3]s>
+        <p id="form-error" class="form-error">{{ with .Ctx.Error }}{{ . }}{{ end }}</p>
+        <input type="email" name="email" value="{{ with .Ctx.Email }}{{ . }}{{ end }}" placeholder="Email" required />
+        <input type="password" name="password" placeholder="Password" required />
+        <button type="submit">%[1]s</button>
+      </form>
+      <p>%[4]s</p>
+    </div>
+</body>`
+	tailwindAuthFormBodyExampleHTML = `
+<body class="max-w-sm mx-auto">
+    <div class="flex flex-col gap-y-6 mt-16 w-full">
+      <h1 class="text-3xl font-bold text-center">%[1]s</h1>
+      <form method="post" action="%[2]s" class="flex flex-col gap-y-4"%[3]s>
+        <p id="form-error" class="text-sm text-red-600">{{ with .Ctx.Error }}{{ . }}{{ end }}</p>
+        <input type="email" name="email" value="{{ with .Ctx.Email }}{{ . }}{{ end }}" placeholder="Email" required class="rounded-md border-gray-300" />
+        <input type="password" name="password" placeholder="Password" required class="rounded-md border-gray-300" />
+        <button type="submit" class="rounded-md bg-blue-600 px-4 py-2 font-medium text-white hover:bg-blue-700">%[1]s</button>
+      </form>
+      <p class="text-sm text-center">%[4]s</p>
+    </div>
+</body>`
+
+	basicContactBodyExampleHTML = `
+<body class="container">
+    <div>
+      <h1>{{ .Ctx.Title }}</h1>
+      <form method="post" action="/contact" class="form"%s>
+        <p id="form-status">{{ with .Ctx.Status }}{{ . }}{{ end }}</p>
+        <input type="text" name="name" value="{{ with .Ctx.Name }}{{ . }}{{ end }}" placeholder="Name" required />
+        <input type="email" name="email" value="{{ with .Ctx.Email }}{{ . }}{{ end }}" placeholder="Email" required />
+        <textarea name="message" rows="5" maxlength="5000" placeholder="Message" required>{{ with .Ctx.Message }}{{ . }}{{ end }}</textarea>
+        <button type="submit">Send</button>
+      </form>
+    </div>
+</body>`
+	tailwindContactBodyExampleHTML = `
+<body class="max-w-sm mx-auto">
+    <div class="flex flex-col gap-y-6 mt-16 w-full">
+      <h1 class="text-3xl font-bold text-center">{{ .Ctx.Title }}</h1>
+      <form method="post" action="/contact" class="flex flex-col gap-y-4"%s>
+        <p id="form-status" class="text-sm">{{ with .Ctx.Status }}{{ . }}{{ end }}</p>
+        <input type="text" name="name" value="{{ with .Ctx.Name }}{{ . }}{{ end }}" placeholder="Name" required class="rounded-md border-gray-300" />
+        <input type="email" name="email" value="{{ with .Ctx.Email }}{{ . }}{{ end }}" placeholder="Email" required class="rounded-md border-gray-300" />
+        <textarea name="message" rows="5" maxlength="5000" placeholder="Message" required class="rounded-md border-gray-300">{{ with .Ctx.Message }}{{ . }}{{ end }}</textarea>
+        <button type="submit" class="rounded-md bg-blue-600 px-4 py-2 font-medium text-white hover:bg-blue-700">Send</button>
+      </form>
+    </div>
+</body>`
+
+	basicPageBodyExampleHTML = `
+<body class="container">
+    <div>
+      <h1>{{ .Ctx.Title }}</h1>
+    </div>
+</body>`
+	tailwindPageBodyExampleHTML = `
+<body class="max-w-3xl mx-auto">
+    <div class="flex flex-col items-center gap-y-6 mt-4">
+      <h1 class="text-4xl my-4 font-bold">{{ .Ctx.Title }}</h1>
+    </div>
+</body>`
+
+	basicAccountBodyExampleHTML = `
+<body class="container">
+    <div>
+      <h1>{{ .Ctx.Title }}</h1>
+      <p>Signed in as <strong>{{ .Ctx.Email }}</strong></p>
+      <form method="post" action="/logout"%s>
+        <button type="submit">Logout</button>
+      </form>
+    </div>
+</body>`
+	tailwindAccountBodyExampleHTML = `
+<body class="max-w-sm mx-auto">
+    <div class="flex flex-col items-center gap-y-6 mt-16">
+      <h1 class="text-3xl font-bold">{{ .Ctx.Title }}</h1>
+      <p class="text-lg">Signed in as <strong>{{ .Ctx.Email }}</strong></p>
+      <form method="post" action="/logout"%s>
+        <button type="submit" class="rounded-md bg-gray-900 px-4 py-2 font-medium text-white hover:bg-gray-700">Logout</button>
+      </form>
+    </div>
+</body>`
 )
 
 func generatePageContent(page string, cfg StackConfig) []byte {
@@ -57,10 +192,71 @@ func generatePageContent(page string, cfg StackConfig) []byte {
 		result = processRawErrorPageData(cfg)
 	case "Root.html":
 		result = processRootLayoutPageData(cfg)
+	case "Login.html", "Register.html", "Account.html":
+		result = processRawAuthPageData(page, cfg)
+	case "Contact.html":
+		result = processRawContactPageData(cfg)
 	case "instruction.md":
 		result = generateInstruction()
 	}
 
+	return finalizePageContent(result, cfg)
+}
+
+// generateNewPageContent returns the content of a page added to an existing
+// project with `gospur generate page`.
+func generateNewPageContent(cfg StackConfig) []byte {
+	return finalizePageContent(processRawPageData(generatePageHTMLBody(cfg), cfg), cfg)
+}
+
+// generateComponentContent returns the content of a component added with
+// `gospur generate component`, it's rendered with {{ template "components/<name>" . }}.
+func generateComponentContent(name string, cfg StackConfig) []byte {
+	class := toKebabCase(name)
+	if strings.HasPrefix(cfg.CssStrategy, "Tailwind") {
+		class = "rounded-md border border-gray-200 p-4"
+	}
+
+	return []byte(fmt.Sprintf(`{{ define "components/%s" }}
+<div class="%s">
+  {{ . }}
+</div>
+{{ end }}
+`, name, class))
+}
+
+// generateCrudPageContent returns the content of a `page` (list, show or form)
+// of a resource added with `gospur generate crud`.
+func generateCrudPageContent(page string, res crudResource, cfg StackConfig) []byte {
+	var body string
+
+	switch page {
+	case "list":
+		body = generateCrudListHTMLBody(res, cfg)
+	case "show":
+		body = generateCrudShowHTMLBody(res, cfg)
+	case "form":
+		body = generateCrudFormHTMLBody(res, cfg)
+	}
+
+	return finalizePageContent(processRawPageData(body, cfg), cfg)
+}
+
+// finalizePageContent applies the changes every page needs for the
+// selected extras and formats the result.
+func finalizePageContent(result string, cfg StackConfig) []byte {
+	// The pages are rendered in the locale of the request.
+	if contains(cfg.ExtraOpts, "I18n") {
+		result = strings.ReplaceAll(result, `<html lang="en">`, `<html lang="{{ .Locale }}">`)
+	}
+	// Forms submit the CSRF token in a hidden input, HTMX sends it as a header of every request.
+	if contains(cfg.ExtraOpts, "Security") {
+		result = postFormRe.ReplaceAllString(result, "$1\n{{ csrfField .CSRF }}")
+		if contains(cfg.ExtraOpts, "HTMX") {
+			result = strings.ReplaceAll(result, `<body class="`, `<body hx-headers='{"X-CSRF-Token": "{{ .CSRF }}"}' class="`)
+		}
+	}
+
 	return []byte(gohtml.Format(result))
 }
 
@@ -86,9 +282,9 @@ func processRootLayoutPageData(cfg StackConfig) string {
     <meta charset="UTF-8" />
     <meta name="viewport" content="width=device-width, initial-scale=1.0" />
     %s
-    <!-- For live reloading -->
-    {{ if .IsDev }}
-    <script src="http://localhost:35729/livereload.js"></script>
+    <!-- Live reload by gospur dev -->
+    {{ if and .IsDev .LiveReloadURL }}
+    <script src="{{ .LiveReloadURL }}/reload.js"></script>
     {{ end }}
     %s
 
@@ -118,9 +314,9 @@ func processRawHomePageData(cfg StackConfig) string {
     <meta charset="UTF-8" />
     <meta name="viewport" content="width=device-width, initial-scale=1.0" />
     %s
-    <!-- For live reloading -->
-    {{ if .IsDev }}
-    <script src="http://localhost:35729/livereload.js"></script>
+    <!-- Live reload by gospur dev -->
+    {{ if and .IsDev .LiveReloadURL }}
+    <script src="{{ .LiveReloadURL }}/reload.js"></script>
     {{ end }}
     %s
 
@@ -148,9 +344,9 @@ func processRawErrorPageData(cfg StackConfig) string {
     <meta charset="UTF-8" />
     <meta name="viewport" content="width=device-width, initial-scale=1.0" />
     %s
-    <!-- For live reloading -->
-    {{ if .IsDev }}
-    <script src="http://localhost:35729/livereload.js"></script>
+    <!-- Live reload by gospur dev -->
+    {{ if and .IsDev .LiveReloadURL }}
+    <script src="{{ .LiveReloadURL }}/reload.js"></script>
     {{ end }}
 
     <title>{{ .Ctx.Title }}</title>
@@ -165,26 +361,463 @@ func processRawErrorPageData(cfg StackConfig) string {
 	return errorHTML
 }
 
+func processRawAuthPageData(page string, cfg StackConfig) string {
+	body := generateAuthHTMLBody(page, cfg)
+	if cfg.WebFramework == "Fiber" || cfg.WebFramework == "Chi" {
+		return removeLinesStartEnd(body, 2, 1)
+	}
+
+	authHTML := fmt.Sprintf(`<!DOCTYPE html>
+<html lang="en">
+  <head>
+    <meta charset="UTF-8" />
+    <meta name="viewport" content="width=device-width, initial-scale=1.0" />
+    %s
+    <!-- Live reload by gospur dev -->
+    {{ if and .IsDev .LiveReloadURL }}
+    <script src="{{ .LiveReloadURL }}/reload.js"></script>
+    {{ end }}
+    %s
+
+    <title>{{ .Ctx.Title }}</title>
+	<meta name="title" content="{{ .Ctx.Title }}">
+  </head>
+  %s
+</html>`,
+		generateHeadStyles(cfg),
+		generateHeadScripts(cfg),
+		body,
+	)
+
+	return authHTML
+}
+
+func processRawContactPageData(cfg StackConfig) string {
+	body := generateContactHTMLBody(cfg)
+	if cfg.WebFramework == "Fiber" || cfg.WebFramework == "Chi" {
+		return removeLinesStartEnd(body, 2, 1)
+	}
+
+	contactHTML := fmt.Sprintf(`<!DOCTYPE html>
+<html lang="en">
+  <head>
+    <meta charset="UTF-8" />
+    <meta name="viewport" content="width=device-width, initial-scale=1.0" />
+    %s
+    <!-- Live reload by gospur dev -->
+    {{ if and .IsDev .LiveReloadURL }}
+    <script src="{{ .LiveReloadURL }}/reload.js"></script>
+    {{ end }}
+    %s
+
+    <title>{{ .Ctx.Title }}</title>
+	<meta name="title" content="{{ .Ctx.Title }}">
+  </head>
+  %s
+</html>`,
+		generateHeadStyles(cfg),
+		generateHeadScripts(cfg),
+		body,
+	)
+
+	return contactHTML
+}
+
+func processRawPageData(body string, cfg StackConfig) string {
+	if cfg.WebFramework == "Fiber" || cfg.WebFramework == "Chi" {
+		return removeLinesStartEnd(body, 2, 1)
+	}
+
+	pageHTML := fmt.Sprintf(`<!DOCTYPE html>
+<html lang="en">
+  <head>
+    <meta charset="UTF-8" />
+    <meta name="viewport" content="width=device-width, initial-scale=1.0" />
+    %s
+    <!-- Live reload by gospur dev -->
+    {{ if and .IsDev .LiveReloadURL }}
+    <script src="{{ .LiveReloadURL }}/reload.js"></script>
+    {{ end }}
+    %s
+
+    <title>{{ .Ctx.Title }}</title>
+	<meta name="title" content="{{ .Ctx.Title }}">
+  </head>
+  %s
+</html>`,
+		generateHeadStyles(cfg),
+		generateHeadScripts(cfg),
+		body,
+	)
+
+	return pageHTML
+}
+
 func generateHomeHTMLBody(cfg StackConfig) string {
-	if strings.HasPrefix(cfg.CssStrategy, "Tailwind") {
-		return tailwindHomeBodyExampleHTML
+	hasTailwind := strings.HasPrefix(cfg.CssStrategy, "Tailwind")
+
+	var demo string
+	if contains(cfg.ExtraOpts, "WebSocket") {
+		demo += generateWebSocketDemoHTML(cfg, hasTailwind)
+	}
+	if contains(cfg.ExtraOpts, "Storage") {
+		demo += generateUploadFormHTML(cfg, hasTailwind)
+	}
+
+	var body string
+	if hasTailwind {
+		body = fmt.Sprintf(tailwindHomeBodyExampleHTML, demo)
+	} else {
+		body = fmt.Sprintf(basicHomeBodyExampleHTML, demo)
+	}
+	if contains(cfg.ExtraOpts, "I18n") {
+		return localizeHomeHTMLBody(body, hasTailwind)
+	}
+	return body
+}
+
+// localizeHomeHTMLBody swaps the texts of the Home page for their translations
+// from `locales/*.json` and adds a language switcher below the description.
+func localizeHomeHTMLBody(body string, hasTailwind bool) string {
+	var linkClass string
+	if hasTailwind {
+		linkClass = ` class="text-blue-600 underline"`
+	}
+	switcher := fmt.Sprintf(`
+      <p>{{ t .Locale "home.language" }}: <a href="?lang=en"%[1]s>English</a> · <a href="?lang=de"%[1]s>Deutsch</a></p>`, linkClass)
+
+	return strings.NewReplacer(
+		"{{ .Ctx.Title }}", `{{ t .Locale "home.title" }}`,
+		"{{ .Ctx.Desc }}</p>", `{{ t .Locale "home.desc" }}</p>`+switcher,
+	).Replace(body)
+}
+
+// generateWebSocketDemoHTML returns the chat demo of the Home page.
+// With HTMX, it's wired through the htmx ws extension, otherwise by `web/scripts/ws.js`.
+func generateWebSocketDemoHTML(cfg StackConfig, hasTailwind bool) string {
+	var chatAttrs, formAttrs string
+	if contains(cfg.ExtraOpts, "HTMX") {
+		chatAttrs = ` hx-ext="ws" ws-connect="/ws"`
+		formAttrs = ` ws-send`
+	}
+
+	if hasTailwind {
+		return fmt.Sprintf(tailwindWebSocketDemoHTML, chatAttrs, formAttrs)
+	}
+	return fmt.Sprintf(basicWebSocketDemoHTML, chatAttrs, formAttrs)
+}
+
+// generateUploadFormHTML returns the upload form of the Home page.
+// With HTMX, the file is submitted via `hx-post` and a link to it is swapped in place.
+func generateUploadFormHTML(cfg StackConfig, hasTailwind bool) string {
+	var hxAttrs string
+	if contains(cfg.ExtraOpts, "HTMX") {
+		hxAttrs = ` hx-post="/upload" hx-encoding="multipart/form-data" hx-target="#upload-status"`
+	}
+
+	if hasTailwind {
+		return fmt.Sprintf(tailwindUploadFormHTML, hxAttrs)
 	}
-	return basicHomeBodyExampleHTML
+	return fmt.Sprintf(basicUploadFormHTML, hxAttrs)
 }
 
 func generateErrorHTMLBody(cfg StackConfig) string {
-	if strings.HasPrefix(cfg.CssStrategy, "Tailwind") {
+	hasTailwind := strings.HasPrefix(cfg.CssStrategy, "Tailwind")
+
+	if contains(cfg.ExtraOpts, "I18n") {
+		if hasTailwind {
+			return tailwindI18nErrorBodyExampleHTML
+		}
+		return basicI18nErrorBodyExampleHTML
+	}
+	if hasTailwind {
 		return tailwindErrorBodyExampleHTML
 	}
 	return basicErrorBodyExampleHTML
 }
 
+// generateAuthHTMLBody returns the body of the Login, Register or Account page.
+// With HTMX, forms are submitted via `hx-post` and errors are swapped in place.
+func generateAuthHTMLBody(page string, cfg StackConfig) string {
+	var (
+		hasTailwind = strings.HasPrefix(cfg.CssStrategy, "Tailwind")
+		hasHTMX     = contains(cfg.ExtraOpts, "HTMX")
+		linkClass   string
+	)
+	if hasTailwind {
+		linkClass = ` class="text-blue-600 underline"`
+	}
+
+	if page == "Account.html" {
+		var hxAttrs string
+		if hasHTMX {
+			hxAttrs = ` hx-post="/logout"`
+		}
+		if hasTailwind {
+			return fmt.Sprintf(tailwindAccountBodyExampleHTML, hxAttrs)
+		}
+		return fmt.Sprintf(basicAccountBodyExampleHTML, hxAttrs)
+	}
+
+	var title, action, footer string
+	if page == "Login.html" {
+		title, action = "Login", "/login"
+		footer = fmt.Sprintf(`Don't have an account? <a href="/register"%s>Register</a>`, linkClass)
+	} else {
+		title, action = "Register", "/register"
+		footer = fmt.Sprintf(`Already have an account? <a href="/login"%s>Login</a>`, linkClass)
+	}
+
+	var hxAttrs string
+	if hasHTMX {
+		hxAttrs = fmt.Sprintf(` hx-post="%s" hx-target="#form-error"`, action)
+	}
+
+	if hasTailwind {
+		return fmt.Sprintf(tailwindAuthFormBodyExampleHTML, title, action, hxAttrs, footer)
+	}
+	return fmt.Sprintf(basicAuthFormBodyExampleHTML, title, action, hxAttrs, footer)
+}
+
+// generateContactHTMLBody returns the body of the Contact page.
+// With HTMX, the form is submitted via `hx-post` and the status is swapped in place.
+func generateContactHTMLBody(cfg StackConfig) string {
+	var hxAttrs string
+	if contains(cfg.ExtraOpts, "HTMX") {
+		hxAttrs = ` hx-post="/contact" hx-target="#form-status"`
+	}
+
+	if strings.HasPrefix(cfg.CssStrategy, "Tailwind") {
+		return fmt.Sprintf(tailwindContactBodyExampleHTML, hxAttrs)
+	}
+	return fmt.Sprintf(basicContactBodyExampleHTML, hxAttrs)
+}
+
+// generatePageHTMLBody returns the body of a page added with `gospur generate page`.
+func generatePageHTMLBody(cfg StackConfig) string {
+	if strings.HasPrefix(cfg.CssStrategy, "Tailwind") {
+		return tailwindPageBodyExampleHTML
+	}
+	return basicPageBodyExampleHTML
+}
+
+// generateCrudListHTMLBody returns the body of the page listing every item of a resource.
+// With HTMX, the row of a deleted item is removed without reloading the page.
+func generateCrudListHTMLBody(res crudResource, cfg StackConfig) string {
+	cls := getCrudPageClasses(cfg)
+
+	var head, cells strings.Builder
+	for i, field := range res.Fields {
+		head.WriteString(fmt.Sprintf("\n            <th>%s</th>", field.Label))
+
+		value := crudFieldValueHTML(field, ".")
+		if i == 0 {
+			value = fmt.Sprintf(`<a href="%s/{{ .ID }}"%s>%s</a>`, res.Route, classAttr(cls.link), value)
+		}
+		cells.WriteString(fmt.Sprintf("\n            <td>%s</td>", value))
+	}
+
+	var hxAttrs, csrf string
+	if contains(cfg.ExtraOpts, "HTMX") {
+		hxAttrs = fmt.Sprintf(
+			` hx-post="%s/{{ .ID }}/delete" hx-target="closest tr" hx-swap="outerHTML" hx-confirm="Delete this %s?"`,
+			res.Route, res.Phrase,
+		)
+	}
+	// Inside of range, the token has to be taken from the root.
+	if contains(cfg.ExtraOpts, "Security") {
+		csrf = "\n                {{ csrfField $.CSRF }}"
+	}
+
+	return fmt.Sprintf(`
+<body class="%[1]s">
+    <div%[2]s>
+      <h1%[3]s>{{ .Ctx.Title }}</h1>
+      <a href="%[4]s/new"%[5]s>New %[6]s</a>
+      <table%[7]s>
+        <thead>
+          <tr>%[8]s
+            <th></th>
+          </tr>
+        </thead>
+        <tbody>
+          {{ range .Ctx.Items }}
+          <tr>%[9]s
+            <td>
+              <a href="%[4]s/{{ .ID }}/edit"%[5]s>Edit</a>
+              <form action="%[4]s/{{ .ID }}/delete" method="post"%[10]s>%[11]s
+                <button type="submit"%[12]s>Delete</button>
+              </form>
+            </td>
+          </tr>
+          {{ else }}
+          <tr>
+            <td colspan="%[13]d">No %[14]s yet.</td>
+          </tr>
+          {{ end }}
+        </tbody>
+      </table>
+    </div>
+</body>`,
+		cls.body, classAttr(cls.container), classAttr(cls.heading), res.Route, classAttr(cls.link),
+		res.Human, classAttr(cls.table), head.String(), cells.String(), hxAttrs, csrf,
+		classAttr(cls.delete), len(res.Fields)+1, res.PluralPhrase,
+	)
+}
+
+// generateCrudShowHTMLBody returns the body of the page showing a single item of a resource.
+func generateCrudShowHTMLBody(res crudResource, cfg StackConfig) string {
+	cls := getCrudPageClasses(cfg)
+
+	var fields strings.Builder
+	for _, field := range res.Fields {
+		fields.WriteString(fmt.Sprintf(
+			"\n        <dt%s>%s</dt>\n        <dd>%s</dd>",
+			classAttr(cls.label), field.Label, crudFieldValueHTML(field, ".Ctx.Item"),
+		))
+	}
+
+	return fmt.Sprintf(`
+<body class="%[1]s">
+    <div%[2]s>
+      <h1%[3]s>{{ .Ctx.Title }}</h1>
+      <dl>%[4]s
+      </dl>
+      <a href="%[5]s/{{ .Ctx.Item.ID }}/edit"%[6]s>Edit</a>
+      <form method="post" action="%[5]s/{{ .Ctx.Item.ID }}/delete">
+        <button type="submit"%[7]s>Delete</button>
+      </form>
+      <a href="%[5]s"%[6]s>Back</a>
+    </div>
+</body>`,
+		cls.body, classAttr(cls.container), classAttr(cls.heading), fields.String(),
+		res.Route, classAttr(cls.link), classAttr(cls.delete),
+	)
+}
+
+// generateCrudFormHTMLBody returns the body of the page to create or edit an item of a resource.
+// With HTMX, the form is submitted via `hx-post` and the error is swapped in place.
+func generateCrudFormHTMLBody(res crudResource, cfg StackConfig) string {
+	cls := getCrudPageClasses(cfg)
+
+	var inputs strings.Builder
+	for _, field := range res.Fields {
+		value := fmt.Sprintf("{{ .Ctx.Item.%s }}", field.Name)
+
+		var input string
+		switch field.Type {
+		case "text":
+			input = fmt.Sprintf(`<textarea name="%s" rows="5" required%s>%s</textarea>`, field.Key, classAttr(cls.input), value)
+		case "int":
+			input = fmt.Sprintf(`<input type="number" step="1" name="%s" value="%s" required%s />`, field.Key, value, classAttr(cls.input))
+		case "float":
+			input = fmt.Sprintf(`<input type="number" step="any" name="%s" value="%s" required%s />`, field.Key, value, classAttr(cls.input))
+		case "bool":
+			// The checked attribute can't be toggled inside of the tag.
+			inputs.WriteString(fmt.Sprintf(`
+        <label%[1]s>
+          {{ if .Ctx.Item.%[2]s }}
+          <input type="checkbox" name="%[3]s" checked />
+          {{ else }}
+          <input type="checkbox" name="%[3]s" />
+          {{ end }}
+          %[4]s
+        </label>`, classAttr(cls.label), field.Name, field.Key, field.Label))
+			continue
+		default:
+			input = fmt.Sprintf(`<input type="text" name="%s" value="%s" required%s />`, field.Key, value, classAttr(cls.input))
+		}
+
+		inputs.WriteString(fmt.Sprintf(`
+        <label%s>
+          %s
+          %s
+        </label>`, classAttr(cls.label), field.Label, input))
+	}
+
+	var hxAttrs string
+	if contains(cfg.ExtraOpts, "HTMX") {
+		hxAttrs = ` hx-post="{{ .Ctx.Action }}" hx-target="#form-error"`
+	}
+
+	return fmt.Sprintf(`
+<body class="%[1]s">
+    <div%[2]s>
+      <h1%[3]s>{{ .Ctx.Title }}</h1>
+      <form method="post" action="{{ .Ctx.Action }}"%[4]s%[5]s>
+        <p id="form-error"%[6]s>{{ with .Ctx.Error }}{{ . }}{{ end }}</p>%[7]s
+        <button type="submit"%[8]s>Save</button>
+      </form>
+      <a href="%[9]s"%[10]s>Back</a>
+    </div>
+</body>`,
+		cls.body, classAttr(cls.container), classAttr(cls.heading), classAttr(cls.form), hxAttrs,
+		classAttr(cls.err), inputs.String(), classAttr(cls.button), res.Route, classAttr(cls.link),
+	)
+}
+
+// crudFieldValueHTML returns the template action showing the `field` of the item at `dot`.
+func crudFieldValueHTML(field crudField, dot string) string {
+	if dot == "." {
+		dot = ""
+	}
+	if field.IsBool {
+		return fmt.Sprintf("{{ if %s.%s }}Yes{{ else }}No{{ end }}", dot, field.Name)
+	}
+
+	return fmt.Sprintf("{{ %s.%s }}", dot, field.Name)
+}
+
+// crudPageClasses are the classes used by the pages of a resource.
+type crudPageClasses struct {
+	body, container, heading, link, table, label, form, input, err, button, delete string
+}
+
+func getCrudPageClasses(cfg StackConfig) crudPageClasses {
+	if strings.HasPrefix(cfg.CssStrategy, "Tailwind") {
+		return crudPageClasses{
+			body:      "max-w-3xl mx-auto",
+			container: "flex flex-col gap-y-6 mt-4 w-full",
+			heading:   "text-3xl font-bold",
+			link:      "text-blue-600 hover:underline",
+			table:     "w-full text-left",
+			label:     "flex flex-col gap-y-1 font-medium",
+			form:      "flex flex-col gap-y-4",
+			input:     "rounded-md border-gray-300",
+			err:       "text-sm text-red-600",
+			button:    "rounded-md bg-blue-600 px-4 py-2 font-medium text-white hover:bg-blue-700",
+			delete:    "text-red-600 hover:underline",
+		}
+	}
+
+	return crudPageClasses{
+		body: "container",
+		form: "form",
+		err:  "form-error",
+	}
+}
+
+// classAttr returns the class attribute for the given classes, if any.
+func classAttr(classes string) string {
+	if len(classes) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(` class="%s"`, classes)
+}
+
 func generateHeadScripts(cfg StackConfig) string {
 	scripts := []string{"<!-- Bundled Javascript -->"}
 
 	if contains(cfg.ExtraOpts, "HTMX") {
+		// The CSP blocks the inline styles htmx adds for its indicators.
+		if contains(cfg.ExtraOpts, "Security") {
+			scripts = append(scripts, `<meta name="htmx-config" content='{"includeIndicatorStyles": false}' />`)
+		}
 		scripts = append(scripts, `<script defer src="public/bundle/htmx.js"></script>`)
 	}
+	if contains(cfg.ExtraOpts, "WebSocket") && !contains(cfg.ExtraOpts, "HTMX") {
+		scripts = append(scripts, `<script defer src="public/bundle/ws.js"></script>`)
+	}
 	if cfg.UILibrary == "Preline" {
 		scripts = append(scripts, `<script defer src="public/bundle/preline.js"></script>`)
 	}
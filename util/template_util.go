@@ -150,6 +150,24 @@ func ValidateStackConfig(cfg StackConfig) error {
 			errors = append(errors, fmt.Sprintf("Invalid Extra: %s", opt))
 		}
 	}
+	if contains(cfg.ExtraOpts, "Auth") && cfg.RenderingStrategy == "Seperate" {
+		errors = append(errors, "Extra Auth is only supported with Templates rendering")
+	}
+	if contains(cfg.ExtraOpts, "I18n") && cfg.RenderingStrategy == "Seperate" {
+		errors = append(errors, "Extra I18n is only supported with Templates rendering")
+	}
+	if contains(cfg.ExtraOpts, "OpenAPI") && cfg.RenderingStrategy != "Seperate" {
+		errors = append(errors, "Extra OpenAPI is only supported with Seperate rendering")
+	}
+	if contains(cfg.ExtraOpts, "Compose") && !contains(cfg.ExtraOpts, "Dockerfile") {
+		errors = append(errors, "Extra Compose requires the Dockerfile extra")
+	}
+	if !matchCIOpt(cfg.CIProvider) {
+		errors = append(errors, "Invalid CI Provider")
+	}
+	if len(cfg.CIProvider) != 0 && !contains(cfg.ExtraOpts, "CI") {
+		errors = append(errors, "CI Provider can only be set with the CI extra")
+	}
 
 	if len(errors) > 0 {
 		return fmt.Errorf("\n%s", strings.Join(errors, "\n"))
@@ -264,20 +282,34 @@ func createGitKeepFile(projectDir string) error {
 func preprocessAPIFiles(cfg StackConfig) config.ProjectFiles {
 	parsedApiFiles := make(config.ProjectFiles, 0)
 	for target, paths := range config.ProjectAPIFiles {
-		var templatePath string
-		for _, path := range paths {
-			parts := strings.Split(path, ".")
-			frameworkFromFileName := parts[len(parts)-2]
-			if strings.ToLower(cfg.WebFramework) == frameworkFromFileName {
-				templatePath = path
-			}
+		if skip := skipExtraFile(target, cfg); skip {
+			continue
 		}
-		parsedApiFiles[target] = templatePath
+		parsedApiFiles[target] = frameworkTemplatePath(paths, cfg.WebFramework)
 	}
 
 	return parsedApiFiles
 }
 
+// frameworkTemplatePath picks the template of the `framework` from the `paths`,
+// named like `api.go.echo.tmpl`. A single path is shared by every framework.
+func frameworkTemplatePath(paths []string, framework string) string {
+	if len(paths) == 1 {
+		return paths[0]
+	}
+
+	var templatePath string
+	for _, path := range paths {
+		parts := strings.Split(path, ".")
+		frameworkFromFileName := parts[len(parts)-2]
+		if strings.ToLower(framework) == frameworkFromFileName {
+			templatePath = path
+		}
+	}
+
+	return templatePath
+}
+
 // preprocessAPIFiles takes `StackConfig` and processes the API Files to
 // strip, exclude any unnecessary files or configuration based on the `StackConfig`.
 func preprocessBaseFiles(cfg StackConfig) config.ProjectFiles {
@@ -305,6 +337,10 @@ func preprocessPageFiles(cfg StackConfig) config.ProjectFiles {
 		if cfg.RenderingStrategy != "Seperate" && strings.HasSuffix(target, "instruction.md") {
 			continue
 		}
+		// Skip pages which belong to an extra option that isn't selected.
+		if skip := skipExtraFile(target, cfg); skip {
+			continue
+		}
 		// Skip layouts dir if not supported.
 		if strings.HasPrefix(target, "web/layouts") && (cfg.WebFramework != "Fiber" && cfg.WebFramework != "Chi") {
 			continue
@@ -321,21 +357,50 @@ func preprocessPageFiles(cfg StackConfig) config.ProjectFiles {
 //
 // Info: Should be only valid for Base Files.
 func skipProjectfiles(filePath string, cfg StackConfig) bool {
-	if cfg.RenderingStrategy == "Seperate" && isFrontendFile(filePath) {
+	// The Swagger UI of OpenAPI is served by the server itself.
+	if cfg.RenderingStrategy == "Seperate" && isFrontendFile(filePath) && !strings.HasPrefix(filePath, "openapi/") {
 		return true
 	}
 	// Skip tailwind config if tailwind is not selected as a CSS Strategy.
 	if filePath == "tailwind.config.js" && cfg.CssStrategy != "Tailwind3" {
 		return true
 	}
-	// Skip Dockerfile and dockerignore if not selected in extra options.
-	if (filePath == "Dockerfile" || filePath == ".dockerignore") && !contains(cfg.ExtraOpts, "Dockerfile") {
+	// Skip files of extra options (eg. Dockerfile) which are not selected.
+	if skip := skipExtraFile(filePath, cfg); skip {
+		return true
+	}
+	// With HTMX, the WebSocket demo uses the htmx ws extension instead of the plain client.
+	hasHTMX := contains(cfg.ExtraOpts, "HTMX")
+	if (filePath == "web/scripts/ws.js" && hasHTMX) || (filePath == "web/scripts/htmx.js" && !hasHTMX) {
+		return true
+	}
+	// CSRF tokens are only needed by the pages, CORS only by a seperate client.
+	if (filePath == "security/csrf.go" && cfg.RenderingStrategy == "Seperate") ||
+		(filePath == "security/cors.go" && cfg.RenderingStrategy != "Seperate") {
 		return true
 	}
 
 	return false
 }
 
+// skipExtraFile returns true if the given project file belongs to
+// an extra option which is not selected in the `StackConfig`.
+func skipExtraFile(filePath string, cfg StackConfig) bool {
+	for opt, files := range config.ProjectExtraFiles {
+		if contains(files, filePath) && !contains(cfg.ExtraOpts, opt) {
+			return true
+		}
+	}
+	// Only the pipeline of the chosen CI provider is created.
+	for provider, file := range config.ProjectCIFiles {
+		if file == filePath && provider != cfg.CIProvider {
+			return true
+		}
+	}
+
+	return false
+}
+
 func matchFrameworkOpt(v string) bool {
 	switch v {
 	case "Echo":
@@ -395,6 +460,46 @@ func matchExtraOpt(v string) bool {
 		return true
 	case "Dockerfile":
 		return true
+	case "Auth":
+		return true
+	case "OpenAPI":
+		return true
+	case "CI":
+		return true
+	case "Compose":
+		return true
+	case "Observability":
+		return true
+	case "Worker":
+		return true
+	case "WebSocket":
+		return true
+	case "I18n":
+		return true
+	case "Mail":
+		return true
+	case "Security":
+		return true
+	case "RateLimit":
+		return true
+	case "Storage":
+		return true
+	// Can be empty if not chosen
+	case "":
+		return true
+	default:
+		return false
+	}
+}
+
+func matchCIOpt(v string) bool {
+	switch v {
+	case "GitHub":
+		return true
+	case "GitLab":
+		return true
+	case "Gitea":
+		return true
 	// Can be empty if not chosen
 	case "":
 		return true
@@ -28,4 +28,60 @@ func TestSkipProjectfiles(t *testing.T) {
 	mockStackCfg.CssStrategy = "Vanilla"
 	skip = skipProjectfiles("tailwind.config.js", mockStackCfg)
 	a.True(skip)
+
+	// Without HTMX, the WebSocket demo uses the plain client.
+	mockStackCfg.ExtraOpts = []string{"WebSocket"}
+	a.False(skipProjectfiles("web/scripts/ws.js", mockStackCfg))
+	a.True(skipProjectfiles("web/scripts/htmx.js", mockStackCfg))
+
+	// With HTMX, it uses the htmx ws extension.
+	mockStackCfg.ExtraOpts = []string{"WebSocket", "HTMX"}
+	a.True(skipProjectfiles("web/scripts/ws.js", mockStackCfg))
+	a.False(skipProjectfiles("web/scripts/htmx.js", mockStackCfg))
+
+	// CSRF protection is only for templates, CORS only for a seperate client.
+	mockStackCfg.ExtraOpts = []string{"Security"}
+	mockStackCfg.RenderingStrategy = "Templates"
+	a.False(skipProjectfiles("security/csrf.go", mockStackCfg))
+	a.True(skipProjectfiles("security/cors.go", mockStackCfg))
+
+	mockStackCfg.RenderingStrategy = "Seperate"
+	a.True(skipProjectfiles("security/csrf.go", mockStackCfg))
+	a.False(skipProjectfiles("security/cors.go", mockStackCfg))
+	a.False(skipProjectfiles("security/headers.go", mockStackCfg))
+
+	// The Swagger UI is served by the server, even with a seperate client.
+	mockStackCfg.ExtraOpts = []string{"OpenAPI"}
+	a.False(skipProjectfiles("openapi/docs/init.js", mockStackCfg))
+}
+
+func TestSkipExtraFile(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	mockStackCfg := StackConfig{
+		WebFramework:      "Echo",
+		RenderingStrategy: "Templates",
+	}
+
+	// Files of an extra option are skipped if it's not selected.
+	a.True(skipExtraFile("Dockerfile", mockStackCfg))
+	a.True(skipExtraFile("auth/session.go", mockStackCfg))
+	a.True(skipExtraFile("web/Login.html", mockStackCfg))
+
+	// Files which don't belong to any extra option are never skipped.
+	a.False(skipExtraFile("main.go", mockStackCfg))
+
+	// Files of a selected extra option are not skipped.
+	mockStackCfg.ExtraOpts = []string{"Auth"}
+	a.False(skipExtraFile("auth/session.go", mockStackCfg))
+	a.False(skipExtraFile("api/auth.go", mockStackCfg))
+	a.True(skipExtraFile(".dockerignore", mockStackCfg))
+
+	// Only the pipeline of the chosen CI provider is created.
+	mockStackCfg.ExtraOpts = []string{"CI"}
+	mockStackCfg.CIProvider = "GitLab"
+	a.False(skipExtraFile(".gitlab-ci.yml", mockStackCfg))
+	a.True(skipExtraFile(".github/workflows/ci.yml", mockStackCfg))
+	a.True(skipExtraFile(".gitea/workflows/ci.yml", mockStackCfg))
 }
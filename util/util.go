@@ -5,14 +5,17 @@ import (
 	"archive/zip"
 	"bytes"
 	"compress/gzip"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
-	"runtime"
 	"strings"
 
 	"github.com/nilotpaul/gospur/config"
+	"golang.org/x/mod/semver"
 )
 
 const maxNestingDepth = 3
@@ -61,10 +64,37 @@ func CreateTargetDir(path string, strict bool) error {
 	return nil
 }
 
+// gospurModVersion returns the version of the CLI as a module query
+// (eg. v0.8.0) for `go run`, latest if it isn't known.
+func gospurModVersion() string {
+	version, err := config.GetVersion()
+	if err != nil {
+		return "latest"
+	}
+
+	return modVersion(version)
+}
+
+// modVersion returns `version` as a module query if it's a release,
+// otherwise latest. Pseudo-versions (eg. v0.0.0-20250101000000-abcdef123456)
+// and local builds (eg. v0.8.0+dirty) can't be fetched by `go run`.
+func modVersion(version string) string {
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	if !semver.IsValid(version) || semver.Prerelease(version) != "" || semver.Build(version) != "" {
+		return "latest"
+	}
+
+	return version
+}
+
 func MakeProjectCtx(cfg StackConfig, modPath string) map[string]any {
 	return map[string]any{
 		"ModPath": modPath,
-		"IsLinux": strings.Split(runtime.GOOS, "/")[0] == "linux",
+		"AppName": path.Base(modPath),
+		// The generated Makefile runs this version of the CLI if it isn't installed.
+		"GospurVersion": gospurModVersion(),
 		"Web": map[string]bool{
 			"IsEcho":  cfg.WebFramework == "Echo",
 			"IsFiber": cfg.WebFramework == "Fiber",
@@ -85,7 +115,24 @@ func MakeProjectCtx(cfg StackConfig, modPath string) map[string]any {
 			"IsSeperate":  cfg.RenderingStrategy == "Seperate",
 		},
 		"Extras": map[string]bool{
-			"HasHTMX": contains(cfg.ExtraOpts, "HTMX"),
+			"HasHTMX":          contains(cfg.ExtraOpts, "HTMX"),
+			"HasDockerfile":    contains(cfg.ExtraOpts, "Dockerfile"),
+			"HasAuth":          contains(cfg.ExtraOpts, "Auth"),
+			"HasOpenAPI":       contains(cfg.ExtraOpts, "OpenAPI"),
+			"HasCI":            contains(cfg.ExtraOpts, "CI"),
+			"HasCompose":       contains(cfg.ExtraOpts, "Compose"),
+			"HasObservability": contains(cfg.ExtraOpts, "Observability"),
+			"HasWorker":        contains(cfg.ExtraOpts, "Worker"),
+			"HasWebSocket":     contains(cfg.ExtraOpts, "WebSocket"),
+			"HasI18n":          contains(cfg.ExtraOpts, "I18n"),
+			"HasMail":          contains(cfg.ExtraOpts, "Mail"),
+			"HasSecurity":      contains(cfg.ExtraOpts, "Security"),
+			"HasRateLimit":     contains(cfg.ExtraOpts, "RateLimit"),
+			"HasStorage":       contains(cfg.ExtraOpts, "Storage"),
+		},
+		// Secrets written to the local `.env` file.
+		"Secrets": map[string]string{
+			"SessionSecret": generateSecret(32),
 		},
 	}
 }
@@ -243,6 +290,15 @@ func matchBinaryFile(name string) bool {
 	}
 }
 
+// generateSecret returns a random hex encoded secret of `n` bytes.
+func generateSecret(n int) string {
+	b := make([]byte, n)
+	// crypto/rand doesn't fail on any of the supported platforms.
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
 // contains checks if a slice of string contains the given item.
 func contains(slice []string, item string) bool {
 	for _, v := range slice {
@@ -67,3 +67,38 @@ func TestFindMatchingBinary(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateSecret(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	secret := generateSecret(32)
+	// Hex encoding doubles the length.
+	a.Len(secret, 64)
+	// Every call gives a new secret.
+	a.NotEqual(secret, generateSecret(32))
+}
+
+func TestGospurModVersion(t *testing.T) {
+	t.Parallel()
+
+	// Tests are built without version info.
+	assert.Equal(t, "latest", gospurModVersion())
+}
+
+func TestModVersion(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	// Releases are used as is.
+	a.Equal("v0.8.0", modVersion("v0.8.0"))
+	a.Equal("v0.8.0", modVersion("0.8.0"))
+
+	// Anything `go run` can't fetch falls back to latest.
+	a.Equal("latest", modVersion("v0.0.0-20261019153940-bd3f87e155d8"))
+	a.Equal("latest", modVersion("v0.8.1-0.20261019153940-bd3f87e155d8+dirty"))
+	a.Equal("latest", modVersion("v0.8.0+dirty"))
+	a.Equal("latest", modVersion("v0.9.0-rc.1"))
+	a.Equal("latest", modVersion("(devel)"))
+	a.Equal("latest", modVersion(""))
+}